@@ -0,0 +1,96 @@
+package flexid
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Symbol sets which can be added to or removed from an Alphabet.
+const (
+	// AmbiguousChars are characters which are easily confused with one another (0/O, 1/l/I).
+	AmbiguousChars = "01IOl"
+	// VowelChars are removed to avoid IDs spelling out words.
+	VowelChars = "AEIOUaeiou"
+	// UrlUnsafeChars are printable ASCII characters outside the RFC 3986 unreserved set.
+	UrlUnsafeChars = " !\"#$%&'()*+,/:;<=>?@[\\]^`{|}"
+	// ShellUnsafeChars are characters with special meaning to POSIX shells.
+	ShellUnsafeChars = " !\"#$&'()*;<>?[\\]^`{|}~"
+)
+
+// Alphabet builds a custom alphabet from an existing one, e.g. base62 without ambiguous characters.
+// The result of Build can be passed to Config.WithAlphabet.
+type Alphabet struct {
+	chars string
+}
+
+// NewAlphabet starts a builder from the given alphabet, typically one of the built-in constants.
+func NewAlphabet(base string) Alphabet {
+	return Alphabet{chars: base}
+}
+
+// Without removes every character in the given sets from the alphabet.
+func (a Alphabet) Without(sets ...string) Alphabet {
+	removed := strings.Join(sets, "")
+	var sb strings.Builder
+	for _, ch := range a.chars {
+		if !strings.ContainsRune(removed, ch) {
+			sb.WriteRune(ch)
+		}
+	}
+	a.chars = sb.String()
+	return a
+}
+
+// With appends the characters in the given sets which aren't already in the alphabet.
+func (a Alphabet) With(sets ...string) Alphabet {
+	var sb strings.Builder
+	sb.WriteString(a.chars)
+	for _, set := range sets {
+		for _, ch := range set {
+			if !strings.ContainsRune(sb.String(), ch) {
+				sb.WriteRune(ch)
+			}
+		}
+	}
+	a.chars = sb.String()
+	return a
+}
+
+// Sorted orders the characters by byte value, so that IDs sort lexicographically in the order
+// they were generated.
+func (a Alphabet) Sorted() Alphabet {
+	chars := []byte(a.chars)
+	slices.Sort(chars)
+	a.chars = string(chars)
+	return a
+}
+
+// Build validates the alphabet and returns it as a string usable in Config.WithAlphabet.
+func (a Alphabet) Build() (string, error) {
+	if len(a.chars) < 2 {
+		return "", errors.New("alphabet must contain at least 2 characters")
+	}
+
+	for i := 0; i < len(a.chars); i++ {
+		if a.chars[i] >= 0x80 {
+			return "", errors.New("alphabet must only contain ASCII characters")
+		}
+	}
+
+	err := validateAlphabet(a.chars)
+	if err != nil {
+		return "", err
+	}
+
+	return a.chars, nil
+}
+
+// MustBuild is like Build, but panics if the alphabet is invalid.
+func (a Alphabet) MustBuild() string {
+	alphabet, err := a.Build()
+	if err != nil {
+		panic("flexid: failed to build alphabet: " + err.Error())
+	}
+	return alphabet
+}
@@ -0,0 +1,79 @@
+package flexid
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Alphabet_Build(t *testing.T) {
+	testCases := []struct {
+		name        string
+		alphabet    Alphabet
+		expected    string
+		expectError bool
+	}{
+		{"Unchanged", NewAlphabet(Base16LowerAlphabet), Base16LowerAlphabet, false},
+		{
+			name:     "Base62 Without Ambiguous",
+			alphabet: NewAlphabet(Base62Alphabet).Without(AmbiguousChars),
+			expected: "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+		},
+		{
+			name:     "Base36 Without Vowels",
+			alphabet: NewAlphabet(Base36Alphabet).Without(VowelChars),
+			expected: "0123456789bcdfghjklmnpqrstvwxyz",
+		},
+		{
+			name:     "Multiple Sets",
+			alphabet: NewAlphabet(Base36Alphabet).Without(AmbiguousChars, VowelChars),
+			expected: "23456789bcdfghjkmnpqrstvwxyz",
+		},
+		{
+			name:     "With Skips Existing",
+			alphabet: NewAlphabet(Base16LowerAlphabet).With("f~g"),
+			expected: "0123456789abcdef~g",
+		},
+		{
+			name:     "Sorted",
+			alphabet: NewAlphabet(Base64UrlAlphabet).Sorted(),
+			expected: "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz",
+		},
+		{"Duplicate In Base", NewAlphabet("abca"), "", true},
+		{"Too Short", NewAlphabet("01").Without("0"), "", true},
+		{"Non-ASCII", NewAlphabet("abc").With("é"), "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.alphabet.Build()
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, but got alphabet %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("Build() got %q, want %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func Test_Alphabet_UrlAndShellSafe(t *testing.T) {
+	alphabet := NewAlphabet(Base62Alphabet).With("-_.~!*").Without(UrlUnsafeChars, ShellUnsafeChars).MustBuild()
+
+	if strings.ContainsAny(alphabet, "!*~") {
+		t.Errorf("Expected unsafe characters to be removed, got %q", alphabet)
+	}
+	if !strings.ContainsAny(alphabet, "-_.") {
+		t.Errorf("Expected safe characters to be kept, got %q", alphabet)
+	}
+
+	gen := MustNewGenerator(NewConfig().WithAlphabet(alphabet))
+	if id := gen.MustGenerate(); !containsOnly(id, alphabet) {
+		t.Errorf("ID %q contains characters outside the built alphabet %q", id, alphabet)
+	}
+}
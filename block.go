@@ -0,0 +1,206 @@
+package flexid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+)
+
+// ErrBlockExhausted is returned by BlockAllocator.Next once every ID in its block has been issued.
+var ErrBlockExhausted = errors.New("block exhausted")
+
+// Block is a contiguous range of IDs reserved by Generator.ReserveBlock. It's described by its first and
+// last IDs, which are ordinary IDs encoded with the reserving generator, so a block can be sent to a
+// client as two strings. IDs in the block share the generator's node ID and differ in their tick and
+// sequence number, counting up from First to Last.
+type Block struct {
+	First string
+	Last  string
+}
+
+// ReserveBlock reserves n consecutive sequence numbers, which are never used by the generator for other
+// IDs or blocks. A BlockAllocator can then issue IDs from the block without contacting the generator, e.g.
+// on a mobile client while offline. The generator must have a sequence, e.g. via WithSequence.
+//
+// A block larger than what's left of the current tick continues into the following ticks, which the
+// generator then skips over, as with OverflowBorrow. With OverflowError, the block must fit in what's left
+// of the current tick, or ErrSequenceExhausted is returned.
+func (g *Generator) ReserveBlock(n int) (Block, error) {
+	if g.config.sequenceWidth == 0 {
+		return Block{}, errors.New("reserving a block requires a sequence")
+	}
+	if n <= 0 {
+		return Block{}, errors.New("block size must be positive")
+	}
+
+	ticks, err := g.currentTicks()
+	if err != nil {
+		return Block{}, err
+	}
+	ticks, err = g.checkHighWaterMark(ticks)
+	if err != nil {
+		return Block{}, err
+	}
+
+	var firstTick, firstSequence, lastTick, lastSequence uint64
+	err = g.updateSequence(func() error {
+		var err error
+		firstTick, firstSequence, lastTick, lastSequence, err = g.reserveSequences(ticks, uint64(n))
+		return err
+	})
+	if err != nil {
+		return Block{}, err
+	}
+	// The block may extend past the persisted high-water mark.
+	_, err = g.checkHighWaterMark(lastTick)
+	if err != nil {
+		return Block{}, err
+	}
+
+	first, err := g.generateSegments(context.Background(), firstTick, firstSequence)
+	if err != nil {
+		return Block{}, err
+	}
+	last := first
+	if n > 1 {
+		last, err = g.generateSegments(context.Background(), lastTick, lastSequence)
+		if err != nil {
+			return Block{}, err
+		}
+	}
+	return Block{First: first, Last: last}, nil
+}
+
+// reserveSequences claims the next n sequence numbers, returning the first and last tick and sequence.
+// The sequence state must be locked.
+func (g *Generator) reserveSequences(ticks, n uint64) (uint64, uint64, uint64, uint64, error) {
+	maxSequence := maxEncodable(g.base, g.config.sequenceWidth)
+	firstTick, firstSequence := ticks, uint64(0)
+	if g.hasSequence && ticks <= g.lastTick {
+		firstTick, firstSequence = g.lastTick, g.sequence+1
+		if g.sequence == maxSequence {
+			firstTick, firstSequence = g.lastTick+1, 0
+		}
+	}
+
+	lastTick, lastSequence := firstTick, firstSequence+n-1
+	if n-1 > maxSequence-firstSequence {
+		// Spill over into the following ticks, each of which holds maxSequence+1 sequence numbers.
+		rest := n - 1 - (maxSequence - firstSequence) - 1
+		lastTick, lastSequence = firstTick+1, rest
+		if maxSequence < math.MaxUint64 {
+			lastTick += rest / (maxSequence + 1)
+			lastSequence = rest % (maxSequence + 1)
+		}
+	}
+	// Without borrowing, the block can't go past the current tick, or the last one used.
+	if g.config.overflowPolicy == OverflowError && lastTick > max(ticks, g.lastTick) {
+		return 0, 0, 0, 0, ErrSequenceExhausted
+	}
+
+	g.hasSequence = true
+	g.lastTick = lastTick
+	g.sequence = lastSequence
+	return firstTick, firstSequence, lastTick, lastSequence, nil
+}
+
+// BlockAllocator issues IDs from a Block reserved by another generator, in order. It's safe for
+// concurrent use.
+type BlockAllocator struct {
+	gen   *Generator
+	block Block
+
+	mu          sync.Mutex
+	ticks       uint64 // Tick of the next ID.
+	sequence    uint64 // Sequence number of the next ID.
+	maxSequence uint64
+	issued      int
+	size        int
+}
+
+// NewBlockAllocator returns an allocator issuing IDs from the block. The generator must have the same
+// configuration as the one which reserved the block, but needn't be the same instance, e.g. it may be
+// running on a client. Only the generator's encoding and random source are used, not its clock.
+func NewBlockAllocator(gen *Generator, block Block) (*BlockAllocator, error) {
+	if gen.config.sequenceWidth == 0 {
+		return nil, errors.New("allocating from a block requires a sequence")
+	}
+
+	first, err := gen.Parse(block.First)
+	if err != nil {
+		return nil, fmt.Errorf("invalid first ID in block: %w", err)
+	}
+	last, err := gen.Parse(block.Last)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last ID in block: %w", err)
+	}
+	if first.Node != last.Node {
+		return nil, errors.New("block spans more than one node ID")
+	}
+	if last.Ticks < first.Ticks || (last.Ticks == first.Ticks && last.Sequence < first.Sequence) {
+		return nil, errors.New("block ends before it starts")
+	}
+
+	// The size may not fit in a uint64 for a crafted block, so it's computed with big.Int.
+	maxSequence := maxEncodable(gen.base, gen.config.sequenceWidth)
+	perTick := new(big.Int).Add(new(big.Int).SetUint64(maxSequence), big.NewInt(1))
+	size := new(big.Int).SetUint64(last.Ticks - first.Ticks)
+	size.Mul(size, perTick)
+	size.Add(size, new(big.Int).SetUint64(last.Sequence))
+	size.Sub(size, new(big.Int).SetUint64(first.Sequence))
+	size.Add(size, big.NewInt(1))
+	if !size.IsInt64() || size.Int64() > math.MaxInt {
+		return nil, errors.New("block is too large")
+	}
+
+	return &BlockAllocator{
+		gen:         gen,
+		block:       block,
+		ticks:       first.Ticks,
+		sequence:    first.Sequence,
+		maxSequence: maxSequence,
+		size:        int(size.Int64()),
+	}, nil
+}
+
+// Next returns the next ID from the block, or ErrBlockExhausted if they've all been issued.
+func (a *BlockAllocator) Next() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.issued == a.size {
+		return "", ErrBlockExhausted
+	}
+
+	var id string
+	switch a.issued {
+	case 0:
+		id = a.block.First
+	case a.size - 1:
+		id = a.block.Last
+	default:
+		var err error
+		id, err = a.gen.generateSegments(context.Background(), a.ticks, a.sequence)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	a.issued++
+	a.sequence++
+	if a.sequence > a.maxSequence || a.sequence == 0 {
+		a.ticks++
+		a.sequence = 0
+	}
+	return id, nil
+}
+
+// Remaining returns how many IDs are left in the block.
+func (a *BlockAllocator) Remaining() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.size - a.issued
+}
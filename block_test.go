@@ -0,0 +1,215 @@
+package flexid
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// allocateAll issues every ID from the block with a fresh allocator.
+func allocateAll(t *testing.T, gen *Generator, block Block) []string {
+	t.Helper()
+	allocator, err := NewBlockAllocator(gen, block)
+	if err != nil {
+		t.Fatalf("NewBlockAllocator failed: %v", err)
+	}
+	var ids []string
+	for {
+		id, err := allocator.Next()
+		if errors.Is(err, ErrBlockExhausted) {
+			return ids
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+}
+
+func Test_ReserveBlock(t *testing.T) {
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	server := MustNewGenerator(config.WithSequence(1, OverflowBorrow))
+
+	*offset = 5 * time.Second
+	server.MustGenerate()
+	block, err := server.ReserveBlock(4)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+	if block.First != "51" || block.Last != "54" {
+		t.Errorf("Expected block 51-54, got %+v", block)
+	}
+
+	// The server carries on after the block.
+	if id := server.MustGenerate(); id != "55" {
+		t.Errorf("Expected the next ID after the block to be 55, got %q", id)
+	}
+
+	// The client's clock doesn't matter.
+	clientConfig, _ := fakeClockTestConfig("0123456789", 0)
+	client := MustNewGenerator(clientConfig.WithSequence(1, OverflowBorrow))
+	ids := allocateAll(t, client, block)
+	expected := []string{"51", "52", "53", "54"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected IDs %v, got %v", expected, ids)
+	}
+}
+
+func Test_ReserveBlock_SpansTicks(t *testing.T) {
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	gen := MustNewGenerator(config.WithSequence(1, OverflowWait))
+
+	*offset = 5 * time.Second
+	block, err := gen.ReserveBlock(15)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+	if block.First != "50" || block.Last != "64" {
+		t.Errorf("Expected block 50-64, got %+v", block)
+	}
+
+	ids := allocateAll(t, gen, block)
+	if len(ids) != 15 || ids[9] != "59" || ids[10] != "60" {
+		t.Errorf("Expected IDs to continue into the next tick, got %v", ids)
+	}
+	for i, id := range ids {
+		parsed, err := gen.Parse(id)
+		if err != nil {
+			t.Fatalf("Parse failed for %q: %v", id, err)
+		}
+		if parsed.Ticks != uint64(5+i/10) || parsed.Sequence != uint64(i%10) {
+			t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+		}
+	}
+
+	// The generator uses the borrowed tick until the clock catches up.
+	if id := gen.MustGenerate(); id != "65" {
+		t.Errorf("Expected 65 after the block, got %q", id)
+	}
+}
+
+func Test_ReserveBlock_NonOverlapping(t *testing.T) {
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	gen := MustNewGenerator(config.WithSequence(1, OverflowBorrow))
+
+	*offset = 5 * time.Second
+	seen := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		block, err := gen.ReserveBlock(7)
+		if err != nil {
+			t.Fatalf("ReserveBlock failed: %v", err)
+		}
+		for _, id := range allocateAll(t, gen, block) {
+			if seen[id] {
+				t.Fatalf("ID %q was issued from more than one block", id)
+			}
+			seen[id] = true
+		}
+	}
+	if len(seen) != 35 {
+		t.Errorf("Expected 35 distinct IDs, got %d", len(seen))
+	}
+}
+
+func Test_ReserveBlock_RandomAndNode(t *testing.T) {
+	config := NewConfig().WithNodeID(3, 1).WithSequence(2, OverflowBorrow)
+	server := MustNewGenerator(config)
+	block, err := server.ReserveBlock(100)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+
+	client := MustNewGenerator(config)
+	ids := allocateAll(t, client, block)
+	if len(ids) != 100 {
+		t.Fatalf("Expected 100 IDs, got %d", len(ids))
+	}
+	if !slices.IsSorted(ids) {
+		t.Errorf("Expected IDs from the block to be ordered")
+	}
+	for _, id := range ids {
+		parsed, err := server.Parse(id)
+		if err != nil {
+			t.Fatalf("Parse failed for %q: %v", id, err)
+		}
+		if parsed.Node != 3 || parsed.Random == "" {
+			t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+		}
+	}
+	if id := server.MustGenerate(); id <= ids[len(ids)-1] {
+		t.Errorf("Expected %q to sort after the block", id)
+	}
+}
+
+func Test_ReserveBlock_OverflowError(t *testing.T) {
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	gen := MustNewGenerator(config.WithSequence(1, OverflowError))
+
+	*offset = 5 * time.Second
+	if _, err := gen.ReserveBlock(10); err != nil {
+		t.Fatalf("Expected a block filling the tick to succeed, got %v", err)
+	}
+	if _, err := gen.ReserveBlock(1); !errors.Is(err, ErrSequenceExhausted) {
+		t.Errorf("Expected ErrSequenceExhausted, got %v", err)
+	}
+}
+
+func Test_ReserveBlock_Errors(t *testing.T) {
+	if _, err := MustNewGenerator(NewConfig()).ReserveBlock(1); err == nil {
+		t.Errorf("Expected an error reserving a block without a sequence")
+	}
+
+	config, _ := fakeClockTestConfig("0123456789", 0)
+	if _, err := MustNewGenerator(config.WithSequence(1, OverflowBorrow)).ReserveBlock(0); err == nil {
+		t.Errorf("Expected an error reserving an empty block")
+	}
+}
+
+func Test_NewBlockAllocator_Errors(t *testing.T) {
+	config, _ := fakeClockTestConfig("0123456789", 0)
+	gen := MustNewGenerator(config.WithSequence(1, OverflowBorrow))
+
+	testCases := []struct {
+		name     string
+		block    Block
+		expected string
+	}{
+		{"Invalid First", Block{First: "5x", Last: "54"}, "invalid first ID"},
+		{"Invalid Last", Block{First: "51", Last: ""}, "invalid last ID"},
+		{"Backwards", Block{First: "54", Last: "51"}, "ends before it starts"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewBlockAllocator(gen, tc.block)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+
+	if _, err := NewBlockAllocator(MustNewGenerator(NewConfig()), Block{}); err == nil {
+		t.Errorf("Expected an error allocating without a sequence")
+	}
+}
+
+func Test_BlockAllocator_Remaining(t *testing.T) {
+	config, _ := fakeClockTestConfig("0123456789", 0)
+	allocator, err := NewBlockAllocator(MustNewGenerator(config.WithSequence(1, OverflowBorrow)), Block{First: "58", Last: "61"})
+	if err != nil {
+		t.Fatalf("NewBlockAllocator failed: %v", err)
+	}
+	for remaining := 4; remaining > 0; remaining-- {
+		if allocator.Remaining() != remaining {
+			t.Errorf("Expected %d remaining, got %d", remaining, allocator.Remaining())
+		}
+		allocator.Next()
+	}
+	if allocator.Remaining() != 0 {
+		t.Errorf("Expected none remaining, got %d", allocator.Remaining())
+	}
+	if _, err := allocator.Next(); !errors.Is(err, ErrBlockExhausted) {
+		t.Errorf("Expected ErrBlockExhausted, got %v", err)
+	}
+}
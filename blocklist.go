@@ -0,0 +1,54 @@
+package flexid
+
+import "strings"
+
+// maxRandomAttempts bounds how many random parts are tried before giving up on an ID.
+const maxRandomAttempts = 100
+
+// EnglishBlocklist is a small built-in list of offensive English words, for use with Config.WithBlocklist.
+var EnglishBlocklist = []string{
+	"anal", "anus", "arse", "ass", "bitch", "boob", "butt", "cock", "coon", "crap", "cum", "cunt", "damn",
+	"dick", "dildo", "dyke", "fag", "fuck", "gook", "homo", "jizz", "kike", "nazi", "nigga", "nigger", "penis",
+	"piss", "poop", "porn", "pussy", "rape", "scum", "sex", "shit", "slut", "spic", "tit", "twat", "vagina",
+	"wank", "whore",
+}
+
+// WithBlocklist sets substrings which generated IDs must not contain, compared case-insensitively.
+// When an ID contains a blocked word, its random part is regenerated. The timestamp prefix cannot be
+// changed, so words which lie entirely within it are ignored.
+func (c Config) WithBlocklist(words ...string) Config {
+	c.blocklist = make([]string, 0, len(words))
+	for _, word := range words {
+		if word != "" {
+			c.blocklist = append(c.blocklist, strings.ToLower(word))
+		}
+	}
+	return c
+}
+
+// containsBlockedWord reports whether a blocked word appears in the ID overlapping one of the given
+// [start, end) ranges of random characters.
+func (g *Generator) containsBlockedWord(id string, randomRanges ...[2]int) bool {
+	if len(g.config.blocklist) == 0 {
+		return false
+	}
+
+	id = strings.ToLower(id)
+	for _, word := range g.config.blocklist {
+		for offset := 0; ; {
+			index := strings.Index(id[offset:], word)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			for _, r := range randomRanges {
+				// Only occurrences overlapping a random range can be avoided by regenerating it.
+				if start < r[1] && start+len(word) > r[0] {
+					return true
+				}
+			}
+			offset = start + 1
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package flexid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Blocklist_RegeneratesRandomPart(t *testing.T) {
+	var offset time.Duration
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		// 61 is 'z' and 10 is 'A' in Base62.
+		WithRandomSource(&sequenceReader{bytes: []byte{61, 61, 61, 61, 61, 10}}).
+		WithBlocklist("ZZ"), &offset)) // Matched case-insensitively
+
+	id := gen.MustGenerate()
+	if id != "0AAAAA" {
+		t.Errorf("Expected blocked random part to be regenerated, got %q", id)
+	}
+
+	stats := gen.Stats()
+	if stats.Generated != 1 || stats.Regenerated != 1 {
+		t.Errorf("Expected 1 generated and 1 regenerated, got %+v", stats)
+	}
+	if stats.RegenerationRate() != 1 {
+		t.Errorf("Expected regeneration rate of 1, got %f", stats.RegenerationRate())
+	}
+}
+
+func Test_Blocklist_IgnoresWordsWithinTimestamp(t *testing.T) {
+	offset := 123 * time.Second
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithAlphabet("0123456789").
+		WithNumRandomChars(2).
+		WithRandomSource(&sequenceReader{bytes: []byte{0, 0, 5}}).
+		WithBlocklist("12", "30"), &offset))
+
+	// "12" lies within the timestamp, so can't be avoided, but "30" overlaps the random part.
+	id := gen.MustGenerate()
+	if id != "12355" {
+		t.Errorf("Expected ID %q, got %q", "12355", id)
+	}
+	if regenerated := gen.Stats().Regenerated; regenerated != 1 {
+		t.Errorf("Expected 1 regeneration, got %d", regenerated)
+	}
+}
+
+func Test_Blocklist_GivesUp(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithRandomSource(&sameByteReader{b: 123}).
+		WithBlocklist("z"))
+
+	_, err := gen.Generate()
+	if err == nil {
+		t.Error("Expected an error when every random part is blocked, but got nil")
+	}
+	if generated := gen.Stats().Generated; generated != 0 {
+		t.Errorf("Expected no IDs to be counted as generated, got %d", generated)
+	}
+}
+
+func Test_Blocklist_English(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithAlphabet(Base36Alphabet).WithBlocklist(EnglishBlocklist...))
+
+	for i := 0; i < 100; i++ {
+		id := gen.MustGenerate()
+		randomPart := id[len(id)-gen.config.numRandomChars:]
+		for _, word := range EnglishBlocklist {
+			if strings.Contains(randomPart, word) {
+				t.Fatalf("ID %q contains blocked word %q", id, word)
+			}
+		}
+	}
+	if generated := gen.Stats().Generated; generated != 100 {
+		t.Errorf("Expected 100 generated IDs, got %d", generated)
+	}
+}
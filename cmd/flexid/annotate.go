@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// annotationLayout is how annotate formats IDs' times.
+const annotationLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// runAnnotate copies the named files, or stdin, to stdout, following each recognized ID with its time,
+// e.g. id[2025-10-16T12:00:00.100Z].
+func runAnnotate(args []string, e env) error {
+	fs := newFlagSet("annotate", "[flags] [file...]", e)
+	config := cliconfig.AddFlags(fs)
+	finderConfig := addFinderFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	gen, s, err := newGenerator(config, e)
+	if err != nil {
+		return err
+	}
+	if s.Tick <= 0 {
+		return errors.New("annotating IDs requires a time component")
+	}
+	finder, err := newIDFinder(gen, s, finderConfig, e.now())
+	if err != nil {
+		return err
+	}
+
+	output := bufio.NewWriter(e.stdout)
+	return scanLines(fs.Args(), e, output, func(line string) error {
+		var sb strings.Builder
+		last := 0
+		for _, m := range finder.find(line) {
+			sb.WriteString(line[last:m.end])
+			fmt.Fprintf(&sb, "[%s]", m.parsed.Time.UTC().Format(annotationLayout))
+			last = m.end
+		}
+		sb.WriteString(line[last:])
+		_, err := output.WriteString(sb.String())
+		return err
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+)
+
+// logConfigArgs are the config flags for the IDs in the log tests, generated 1 second per tick from
+// 2025. testNow is tick 1gaUi.
+var logConfigArgs = []string{"-tick", "1s", "-epoch", "2025-01-01", "-random", "4"}
+
+func Test_Annotate(t *testing.T) {
+	input := "start 1gaUiAAAA ok\n" +
+		"an hour ago: 1gZYeBBBB, and a day ago: 1gE1ACCCC\n" +
+		"in the future 1gaVgDDDD, too short 1gaUi, and something\n" +
+		"no newline 1gaUiEEEE"
+	stdout, stderr, code := runCLI(t, input, append([]string{"annotate"}, logConfigArgs...)...)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	expected := "start 1gaUiAAAA[2025-10-16T12:00:00.000Z] ok\n" +
+		"an hour ago: 1gZYeBBBB[2025-10-16T11:00:00.000Z], and a day ago: 1gE1ACCCC[2025-10-15T12:00:00.000Z]\n" +
+		"in the future 1gaVgDDDD, too short 1gaUi, and something\n" +
+		"no newline 1gaUiEEEE[2025-10-16T12:00:00.000Z]"
+	if stdout != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, stdout)
+	}
+}
+
+func Test_Annotate_Prefix(t *testing.T) {
+	input := "ord_1gaUiAAAA usr_1gaUiBBBB ord1gaUiCCCC 1gaUiDDDD\n"
+
+	stdout, _, _ := runCLI(t, input, append([]string{"annotate", "-prefix", "ord_"}, logConfigArgs...)...)
+	expected := "ord_1gaUiAAAA[2025-10-16T12:00:00.000Z] usr_1gaUiBBBB ord1gaUiCCCC 1gaUiDDDD\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+
+	// A prefix of alphabet characters is stripped from the start of a token.
+	stdout, _, _ = runCLI(t, input, append([]string{"annotate", "-prefix", "ord"}, logConfigArgs...)...)
+	expected = "ord_1gaUiAAAA usr_1gaUiBBBB ord1gaUiCCCC[2025-10-16T12:00:00.000Z] 1gaUiDDDD\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+}
+
+func Test_Annotate_AnyLength(t *testing.T) {
+	stdout, _, _ := runCLI(t, "1gaUiAAAA 1gaUiAAA\n", append([]string{"annotate", "-length", "0"}, logConfigArgs...)...)
+	expected := "1gaUiAAAA[2025-10-16T12:00:00.000Z] 1gaUiAAA[2025-01-05T15:40:38.000Z]\n"
+	if stdout != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout)
+	}
+}
+
+func Test_Annotate_Streaming(t *testing.T) {
+	expectStreamed(t, "start 1gaUiAAAA\n", "start 1gaUiAAAA[2025-10-16T12:00:00.000Z]\n", append([]string{"annotate"}, logConfigArgs...)...)
+}
+
+func Test_Annotate_NoTimeComponent(t *testing.T) {
+	if _, _, code := runCLI(t, "", "annotate", "-tick", "0"); code != 1 {
+		t.Errorf("Expected exit code 1 without a time component, got %d", code)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// newGenerator returns a generator for the config flags, whose clock is the environment's.
+func newGenerator(flags *cliconfig.Flags, e env) (*flexid.Generator, cliconfig.Settings, error) {
+	s, err := flags.Resolve()
+	if err != nil {
+		return nil, cliconfig.Settings{}, err
+	}
+
+	gen, err := flexid.NewGenerator(s.Config().WithTimeProvider(e.now))
+	if err != nil {
+		return nil, cliconfig.Settings{}, err
+	}
+	return gen, s, nil
+}
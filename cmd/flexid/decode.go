@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// decoded is an ID's decoded components, as printed by decode.
+type decoded struct {
+	ID     string     `json:"id"`
+	Time   *time.Time `json:"time,omitempty"`
+	Tick   uint64     `json:"tick"`
+	Random string     `json:"random"`
+	Age    string     `json:"age,omitempty"`
+}
+
+// runDecode decodes the IDs given as arguments, or one per line on stdin if there are none.
+func runDecode(args []string, e env) error {
+	fs := newFlagSet("decode", "[flags] [id...]", e)
+	format := fs.String("format", "text", "output format: text, or json for one object per line")
+	config := cliconfig.AddFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	gen, _, err := newGenerator(config, e)
+	if err != nil {
+		return err
+	}
+
+	ids := fs.Args()
+	if len(ids) == 0 {
+		scanner := bufio.NewScanner(e.stdin)
+		for scanner.Scan() {
+			if id := strings.TrimSpace(scanner.Text()); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read IDs: %w", err)
+		}
+	}
+
+	failed := 0
+	encoder := json.NewEncoder(e.stdout)
+	for _, id := range ids {
+		parsed, err := gen.Parse(id)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "flexid: %v\n", err)
+			failed++
+			continue
+		}
+
+		d := newDecoded(id, parsed, e.now())
+		if *format == "json" {
+			if err := encoder.Encode(d); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintln(e.stdout, d.ID)
+		if d.Time != nil {
+			fmt.Fprintf(e.stdout, "  time:   %s\n", d.Time.Format(time.RFC3339Nano))
+			fmt.Fprintf(e.stdout, "  tick:   %d\n", d.Tick)
+		}
+		fmt.Fprintf(e.stdout, "  random: %s\n", d.Random)
+		if d.Time != nil {
+			fmt.Fprintf(e.stdout, "  age:    %s\n", d.Age)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to decode %d of %d IDs", failed, len(ids))
+	}
+	return nil
+}
+
+// newDecoded returns the ID's decoded components, with its age as of now.
+func newDecoded(id string, parsed flexid.ParsedID, now time.Time) decoded {
+	d := decoded{ID: id, Tick: parsed.Ticks, Random: parsed.Random}
+	if !parsed.Time.IsZero() {
+		t := parsed.Time.UTC()
+		d.Time = &t
+		d.Age = now.Sub(t).Round(time.Millisecond).String()
+	}
+	return d
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_Decode(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "", "decode", "-tick", "100ms", "-random", "2", "J2mCmTab")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	expected := `J2mCmTab
+  time:   2025-04-15T22:35:35.7Z
+  tick:   17447565357
+  random: ab
+  age:    4405h24m24.4s
+`
+	if stdout != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, stdout)
+	}
+}
+
+func Test_Decode_Stdin(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "1gaUiAAAA\n\n1gaUjBBBB\n", "decode", "-format", "json",
+		"-tick", "1s", "-epoch", "2025-01-01", "-random", "4")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines of JSON, got %q", stdout)
+	}
+	var d decoded
+	if err := json.Unmarshal([]byte(lines[1]), &d); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[1], err)
+	}
+	if d.ID != "1gaUjBBBB" || d.Tick != 24926401 || d.Random != "BBBB" || d.Age != "-900ms" {
+		t.Errorf("Unexpected decoding %+v", d)
+	}
+}
+
+func Test_Decode_NoTimeComponent(t *testing.T) {
+	stdout, _, code := runCLI(t, "", "decode", "-tick", "0", "abcde")
+	if code != 0 || stdout != "abcde\n  random: abcde\n" {
+		t.Errorf("Expected just the random part, got %d: %q", code, stdout)
+	}
+}
+
+func Test_Decode_Invalid(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "", "decode", "-tick", "100ms", "-random", "2", "J2mCmTab", "x")
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.HasPrefix(stdout, "J2mCmTab\n") {
+		t.Errorf("Expected valid IDs to still be decoded, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "failed to decode 1 of 2 IDs") {
+		t.Errorf("Expected a summary of failures, got %q", stderr)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// runGen generates IDs, one per line, or as a JSON array.
+func runGen(args []string, e env) error {
+	fs := newFlagSet("gen", "[flags]", e)
+	count := fs.Int("n", 1, "number of IDs to generate")
+	format := fs.String("format", "text", "output format: text or json")
+	config := cliconfig.AddFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(e.stderr, "gen takes no arguments, got %q\n", fs.Args())
+		return errUsage
+	}
+	if *count < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	gen, _, err := newGenerator(config, e)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, *count)
+	for i := range ids {
+		ids[i], err = gen.Generate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(e.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(ids)
+	}
+	for _, id := range ids {
+		fmt.Fprintln(e.stdout, id)
+	}
+	return nil
+}
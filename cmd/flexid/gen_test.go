@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_Gen(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "", "gen", "-n", "3", "-tick", "1s", "-epoch", "2025-01-01", "-random", "4")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	ids := strings.Fields(stdout)
+	if len(ids) != 3 {
+		t.Fatalf("Expected 3 IDs, got %q", stdout)
+	}
+	for _, id := range ids {
+		// 2025-10-16T12:00:00Z is 24926400 seconds after the epoch, "1gaUi" in base 62.
+		if !strings.HasPrefix(id, "1gaUi") || len(id) != 9 {
+			t.Errorf("Unexpected ID %q", id)
+		}
+	}
+}
+
+func Test_Gen_JSON(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "", "gen", "-n", "2", "-format", "json", "-tick", "0", "-alphabet", "base16")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	var ids []string
+	if err := json.Unmarshal([]byte(stdout), &ids); err != nil {
+		t.Fatalf("Expected a JSON array, got %q: %v", stdout, err)
+	}
+	if len(ids) != 2 || len(ids[0]) != 5 || strings.Trim(ids[0], "0123456789abcdef") != "" {
+		t.Errorf("Unexpected IDs %v", ids)
+	}
+}
+
+func Test_Gen_Errors(t *testing.T) {
+	testCases := [][]string{
+		{"gen", "-n", "-1"},
+		{"gen", "-format", "xml"},
+		{"gen", "-epoch", "soon"},
+		{"gen", "extra"},
+	}
+	for _, args := range testCases {
+		if _, _, code := runCLI(t, "", args...); code == 0 {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
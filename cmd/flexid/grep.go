@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// runGrep copies the lines of the named files, or stdin, which contain an ID generated within the given
+// time range to stdout.
+func runGrep(args []string, e env) error {
+	fs := newFlagSet("grep", "[flags] [file...]", e)
+	since := fs.String("since", "", "only match IDs generated at or after this time, or this long ago (e.g. 1h)")
+	until := fs.String("until", "", "only match IDs generated before this time, or this long ago")
+	config := cliconfig.AddFlags(fs)
+	finderConfig := addFinderFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	gen, s, err := newGenerator(config, e)
+	if err != nil {
+		return err
+	}
+	if s.Tick <= 0 {
+		return errors.New("filtering by time requires a time component")
+	}
+	now := e.now()
+	from, err := parseBound(*since, now)
+	if err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	to, err := parseBound(*until, now)
+	if err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+	finder, err := newIDFinder(gen, s, finderConfig, now)
+	if err != nil {
+		return err
+	}
+
+	output := bufio.NewWriter(e.stdout)
+	return scanLines(fs.Args(), e, output, func(line string) error {
+		for _, m := range finder.find(line) {
+			t := m.parsed.Time
+			if (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to)) {
+				_, err := output.WriteString(line)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// parseBound parses a time range bound, given as a time or a duration before now. An empty bound is zero.
+func parseBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return cliconfig.ParseTime(value)
+}
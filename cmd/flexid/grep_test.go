@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// grepInput has lines with IDs from now, an hour ago and a day ago, and one without an ID.
+const grepInput = "now 1gaUiAAAA\n" +
+	"hour 1gZYeBBBB\n" +
+	"day 1gE1ACCCC\n" +
+	"none\n"
+
+func Test_Grep(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"All", nil, "now 1gaUiAAAA\nhour 1gZYeBBBB\nday 1gE1ACCCC\n"},
+		{"Since Duration", []string{"-since", "2h"}, "now 1gaUiAAAA\nhour 1gZYeBBBB\n"},
+		{"Until Duration", []string{"-until", "30m"}, "hour 1gZYeBBBB\nday 1gE1ACCCC\n"},
+		{"Range", []string{"-since", "2h", "-until", "30m"}, "hour 1gZYeBBBB\n"},
+		{"Since Time", []string{"-since", "2025-10-16T11:00:00Z"}, "now 1gaUiAAAA\nhour 1gZYeBBBB\n"},
+		{"Until Time", []string{"-until", "2025-10-16T11:00:00Z"}, "day 1gE1ACCCC\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := append(append([]string{"grep"}, tc.args...), logConfigArgs...)
+			stdout, stderr, code := runCLI(t, grepInput, args...)
+			if code != 0 {
+				t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+			}
+			if stdout != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, stdout)
+			}
+		})
+	}
+}
+
+func Test_Grep_Files(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	if err := os.WriteFile(first, []byte("a 1gaUiAAAA\nb 1gE1ACCCC\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("c 1gZYeBBBB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := append(append([]string{"grep", "-since", "2h"}, logConfigArgs...), first, second)
+	stdout, stderr, code := runCLI(t, "", args...)
+	if code != 0 || stdout != "a 1gaUiAAAA\nc 1gZYeBBBB\n" {
+		t.Errorf("Unexpected result %d %q: %s", code, stdout, stderr)
+	}
+
+	_, _, code = runCLI(t, "", append(append([]string{"grep"}, logConfigArgs...), filepath.Join(dir, "missing.log"))...)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for a missing file, got %d", code)
+	}
+}
+
+func Test_Grep_Streaming(t *testing.T) {
+	expectStreamed(t, "none\nnow 1gaUiAAAA\n", "now 1gaUiAAAA\n", append([]string{"grep"}, logConfigArgs...)...)
+}
+
+func Test_Grep_InvalidBound(t *testing.T) {
+	if _, _, code := runCLI(t, "", "grep", "-since", "last week"); code != 1 {
+		t.Errorf("Expected exit code 1 for an invalid bound, got %d", code)
+	}
+}
+
+func Test_ParseBound(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2025-10-01", time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		bound, err := parseBound(tc.value, now)
+		if err != nil || !bound.Equal(tc.expected) {
+			t.Errorf("parseBound(%q) = %v, %v; expected %v", tc.value, bound, err, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// runInspect explains the config given by the flags: how long its IDs are, when they grow, and how
+// likely they are to collide.
+func runInspect(args []string, e env) error {
+	fs := newFlagSet("inspect", "[flags]", e)
+	config := cliconfig.AddFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(e.stderr, "inspect takes no arguments, got %q\n", fs.Args())
+		return errUsage
+	}
+
+	gen, s, err := newGenerator(config, e)
+	if err != nil {
+		return err
+	}
+	example, err := gen.Generate()
+	if err != nil {
+		return err
+	}
+	parsed, err := gen.Parse(example)
+	if err != nil {
+		return err
+	}
+
+	base := len(s.Alphabet)
+	timeLen := len(example) - s.Random
+	entropy := gen.EntropyBits()
+
+	fmt.Fprintf(e.stdout, "alphabet:  %d characters (%s)\n", base, s.Alphabet)
+	if s.Tick > 0 {
+		fmt.Fprintf(e.stdout, "tick:      %s\n", s.Tick)
+		fmt.Fprintf(e.stdout, "epoch:     %s\n", s.Epoch.Format(time.RFC3339))
+	} else {
+		fmt.Fprintln(e.stdout, "tick:      none (no time component)")
+	}
+	fmt.Fprintf(e.stdout, "example:   %s\n", example)
+	fmt.Fprintf(e.stdout, "length:    %d (%d time + %d random)\n", len(example), timeLen, s.Random)
+	if s.Tick > 0 {
+		fmt.Fprintf(e.stdout, "horizon:   %s\n", horizon(base, timeLen, parsed.Ticks, s))
+	}
+	fmt.Fprintf(e.stdout, "entropy:   %.1f bits per ID (%s possible random parts)\n", entropy, combinations(base, s.Random))
+	if s.Random > 0 {
+		scope := "per tick"
+		if s.Tick <= 0 {
+			scope = "in total"
+		}
+		// Birthday bound: a 50% chance of any collision after about sqrt(2 ln 2 N) IDs.
+		fmt.Fprintf(e.stdout, "collision: 50%% chance after ~%.3g IDs %s\n", math.Sqrt(2*math.Ln2*math.Exp2(entropy)), scope)
+	}
+	return nil
+}
+
+// horizon describes when the time component grows beyond timeLen characters, lengthening IDs.
+func horizon(base, timeLen int, ticks uint64, s cliconfig.Settings) string {
+	nextTicks := new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(timeLen)), nil)
+	if nextTicks.Cmp(new(big.Int).SetUint64(math.MaxUint64)) > 0 {
+		return fmt.Sprintf("the time component stays at %d characters, until the tick count overflows", timeLen)
+	}
+
+	// Convert to seconds since the epoch, which may be far beyond what time.Duration can hold.
+	nanos := new(big.Int).Mul(nextTicks, big.NewInt(int64(s.Tick)))
+	seconds, remainder := new(big.Int).QuoRem(nanos, big.NewInt(int64(time.Second)), new(big.Int))
+	if !seconds.IsInt64() || seconds.Int64() > math.MaxInt64-s.Epoch.Unix() {
+		return fmt.Sprintf("the time component stays at %d characters indefinitely", timeLen)
+	}
+	at := time.Unix(s.Epoch.Unix()+seconds.Int64(), int64(s.Epoch.Nanosecond())+remainder.Int64()).UTC()
+
+	remaining := new(big.Int).Sub(nextTicks, new(big.Int).SetUint64(ticks))
+	return fmt.Sprintf("grows to %d characters at %s, in %s ticks", timeLen+1, at.Format(time.RFC3339), remaining)
+}
+
+// combinations returns base^n, written out if short enough.
+func combinations(base, n int) string {
+	value := new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(n)), nil).String()
+	if len(value) > 15 {
+		return fmt.Sprintf("~%s.%se%d", value[:1], strings.TrimRight(value[1:3], "0"), len(value)-1)
+	}
+	return value
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Inspect(t *testing.T) {
+	stdout, stderr, code := runCLI(t, "", "inspect", "-tick", "1h", "-epoch", "2025-01-01", "-alphabet", "crockford", "-random", "2")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	for _, expected := range []string{
+		"alphabet:  32 characters (0123456789ABCDEFGHJKMNPQRSTVWXYZ)\n",
+		"tick:      1h0m0s\n",
+		"length:    5 (3 time + 2 random)\n",
+		// 32^3 hours after the epoch; the example is generated 6924 hours after it.
+		"horizon:   grows to 4 characters at 2028-09-27T08:00:00Z, in 25844 ticks\n",
+		"entropy:   10.0 bits per ID (1024 possible random parts)\n",
+		"collision: 50% chance after ~37.7 IDs per tick\n",
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, stdout)
+		}
+	}
+}
+
+func Test_Inspect_NoTimeComponent(t *testing.T) {
+	stdout, _, code := runCLI(t, "", "inspect", "-tick", "0", "-random", "3")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if !strings.Contains(stdout, "none (no time component)") || strings.Contains(stdout, "horizon") ||
+		!strings.Contains(stdout, "IDs in total") {
+		t.Errorf("Unexpected output:\n%s", stdout)
+	}
+}
+
+func Test_Combinations(t *testing.T) {
+	if c := combinations(62, 5); c != "916132832" {
+		t.Errorf("Expected 916132832, got %s", c)
+	}
+	if c := combinations(62, 20); c != "~7.04e35" {
+		t.Errorf("Expected ~7.04e35, got %s", c)
+	}
+}
@@ -0,0 +1,110 @@
+// Command flexid generates, decodes and inspects FlexIDs from the command line, and finds them in logs.
+//
+// Usage:
+//
+//	flexid <command> [flags] [args]
+//
+// Run "flexid <command> -h" for a command's flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// env is the environment a command runs in, replaced in tests.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	now    func() time.Time
+}
+
+// command is a subcommand, given its arguments after the command name.
+type command struct {
+	run     func(args []string, e env) error
+	summary string
+}
+
+var commands = map[string]command{
+	"gen":      {runGen, "generate IDs"},
+	"decode":   {runDecode, "decode IDs, printing their time, tick, random part and age"},
+	"inspect":  {runInspect, "explain a config: length, horizon and entropy"},
+	"annotate": {runAnnotate, "follow IDs in text with their time, e.g. id[2025-10-16T12:00:00.100Z]"},
+	"grep":     {runGrep, "print lines containing IDs generated within a time range"},
+	"simulate": {runSimulate, "simulate traffic with a config, reporting collisions and ID lengths"},
+}
+
+// errUsage is returned by commands given invalid flags or arguments, after the problem has been reported.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, now: time.Now}))
+}
+
+// run runs the command named by the first argument, returning the process's exit code.
+func run(args []string, e env) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printUsage(e.stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "flexid: unknown command %q\n", args[0])
+		printUsage(e.stderr)
+		return 2
+	}
+
+	err := cmd.run(args[1:], e)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(e.stderr, "flexid: %v\n", err)
+		return 1
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: flexid <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "flexid <command> -h" for a command's flags.`)
+}
+
+// newFlagSet returns a flag set for the named command, reporting errors to stderr.
+func newFlagSet(name, usage string, e env) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: flexid %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags, translating errors other than -h into errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testNow is the fixed current time for CLI tests.
+var testNow = time.Date(2025, 10, 16, 12, 0, 0, 100_000_000, time.UTC)
+
+// runCLI runs the CLI with the given stdin and arguments, returning its output and exit code.
+func runCLI(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, env{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		now:    func() time.Time { return testNow },
+	})
+	return stdout.String(), stderr.String(), code
+}
+
+// chanWriter is a writer which sends each write on the channel.
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+// expectStreamed runs the CLI with the given arguments, writing the input line to its stdin and expecting
+// the output before stdin is closed.
+func expectStreamed(t *testing.T, input, expected string, args ...string) {
+	t.Helper()
+	stdin, w := io.Pipe()
+	stdout := make(chanWriter, 1)
+	done := make(chan int)
+	go func() {
+		done <- run(args, env{stdin: stdin, stdout: stdout, stderr: io.Discard, now: func() time.Time { return testNow }})
+	}()
+
+	if _, err := io.WriteString(w, input); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-stdout:
+		if got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected %q to be written before stdin is closed", expected)
+	}
+	w.Close()
+	<-done
+}
+
+func Test_Run_Usage(t *testing.T) {
+	_, stderr, code := runCLI(t, "")
+	if code != 2 || !strings.Contains(stderr, "Commands:") {
+		t.Errorf("Expected usage and exit code 2, got %d: %s", code, stderr)
+	}
+
+	_, stderr, code = runCLI(t, "", "frobnicate")
+	if code != 2 || !strings.Contains(stderr, `unknown command "frobnicate"`) {
+		t.Errorf("Expected an unknown command error and exit code 2, got %d: %s", code, stderr)
+	}
+}
+
+func Test_Run_FlagErrors(t *testing.T) {
+	_, stderr, code := runCLI(t, "", "gen", "-bogus")
+	if code != 2 || !strings.Contains(stderr, "-bogus") {
+		t.Errorf("Expected a flag error and exit code 2, got %d: %s", code, stderr)
+	}
+
+	_, stderr, code = runCLI(t, "", "gen", "-h")
+	if code != 0 || !strings.Contains(stderr, "Usage: flexid gen") {
+		t.Errorf("Expected help and exit code 0, got %d: %s", code, stderr)
+	}
+}
+
+func Test_Run_CommandErrors(t *testing.T) {
+	_, stderr, code := runCLI(t, "", "gen", "-alphabet", "aa")
+	if code != 1 || !strings.HasPrefix(stderr, "flexid: ") {
+		t.Errorf("Expected an error and exit code 1, got %d: %s", code, stderr)
+	}
+}
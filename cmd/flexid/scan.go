@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// finderFlags are the flags controlling how IDs are recognized in text.
+type finderFlags struct {
+	prefix string
+	length int
+}
+
+// addFinderFlags registers the flags controlling how IDs are recognized.
+func addFinderFlags(fs *flag.FlagSet) *finderFlags {
+	f := &finderFlags{}
+	fs.StringVar(&f.prefix, "prefix", "", "only recognize IDs immediately preceded by this prefix")
+	fs.IntVar(&f.length, "length", -1, "only recognize IDs of this length (default: the length of an ID generated now; 0 for any)")
+	return f
+}
+
+// idFinder recognizes IDs in text. As plenty of ordinary words are valid IDs under most configs, a token
+// is only taken to be an ID if it's a maximal run of alphabet characters of the expected length, following
+// the prefix if any, which parses and whose time isn't in the future.
+type idFinder struct {
+	gen      *flexid.Generator
+	inAlpha  [256]bool
+	prefix   string
+	length   int
+	notAfter time.Time
+}
+
+// match is an ID found in a line, at line[start:end].
+type match struct {
+	start, end int
+	parsed     flexid.ParsedID
+}
+
+// newIDFinder returns a finder for IDs generated with the given config.
+func newIDFinder(gen *flexid.Generator, s cliconfig.Settings, f *finderFlags, now time.Time) (*idFinder, error) {
+	finder := &idFinder{gen: gen, prefix: f.prefix, length: f.length, notAfter: now}
+	for i := 0; i < len(s.Alphabet); i++ {
+		finder.inAlpha[s.Alphabet[i]] = true
+	}
+	if finder.length < 0 {
+		example, err := gen.Generate()
+		if err != nil {
+			return nil, err
+		}
+		finder.length = len(example)
+	}
+	return finder, nil
+}
+
+// find returns the IDs in the line, in order.
+func (f *idFinder) find(line string) []match {
+	var matches []match
+	for i := 0; i < len(line); {
+		if !f.inAlpha[line[i]] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && f.inAlpha[line[i]] {
+			i++
+		}
+		if m, ok := f.match(line, start, i); ok {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// match checks whether the run of alphabet characters line[start:end] is an ID. If there's a prefix,
+// the run may begin with it, when the prefix itself consists of alphabet characters.
+func (f *idFinder) match(line string, start, end int) (match, bool) {
+	if f.prefix != "" {
+		switch {
+		case strings.HasPrefix(line[start:end], f.prefix):
+			start += len(f.prefix)
+		case !strings.HasSuffix(line[:start], f.prefix):
+			return match{}, false
+		}
+	}
+	if start == end || (f.length > 0 && end-start != f.length) {
+		return match{}, false
+	}
+
+	parsed, err := f.gen.Parse(line[start:end])
+	if err != nil || parsed.Time.After(f.notAfter) {
+		return match{}, false
+	}
+	return match{start: start, end: end, parsed: parsed}, true
+}
+
+// scanLines calls fn with each line of the named files, or stdin if there are none, including its newline.
+// The output fn writes to is flushed whenever the input has no more buffered data, so output keeps up with
+// streaming input, e.g. from tail -f.
+func scanLines(paths []string, e env, output *bufio.Writer, fn func(line string) error) error {
+	if len(paths) == 0 {
+		return scanReader(e.stdin, output, fn)
+	}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = scanReader(file, output, fn)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func scanReader(r io.Reader, output *bufio.Writer, fn func(line string) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if fnErr := fn(line); fnErr != nil {
+				return fnErr
+			}
+		}
+		if reader.Buffered() == 0 {
+			if flushErr := output.Flush(); flushErr != nil {
+				return flushErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// traffic is a simulated traffic profile, per node.
+type traffic struct {
+	rate       float64       // Steady IDs per second.
+	burst      int           // Extra IDs generated at once in each burst.
+	burstEvery time.Duration // Interval between bursts.
+	duration   time.Duration // Simulated duration.
+	nodes      int           // Number of nodes, each with its own generator.
+}
+
+// simulation is the outcome of one or more simulated trials.
+type simulation struct {
+	ids             int         // Number of IDs generated, across all trials.
+	collisions      int         // IDs identical to an earlier one in the same trial.
+	collidingTrials int         // Trials with at least one collision.
+	maxPerTick      int         // Most IDs generated in a single tick, across all nodes.
+	lengths         map[int]int // Number of IDs of each length.
+	trials          int
+	profile         traffic
+}
+
+// runSimulate generates IDs for a traffic profile with a simulated clock, reporting collisions, the
+// busiest tick and ID lengths. Unlike the birthday bound, this captures bursty traffic.
+func runSimulate(args []string, e env) error {
+	fs := newFlagSet("simulate", "[flags]", e)
+	var t traffic
+	fs.Float64Var(&t.rate, "rate", 1000, "steady IDs per second, per node")
+	fs.IntVar(&t.burst, "burst", 0, "extra IDs generated at once in each burst, per node")
+	fs.DurationVar(&t.burstEvery, "burst-every", time.Second, "interval between bursts")
+	fs.DurationVar(&t.duration, "duration", 10*time.Second, "simulated duration")
+	fs.IntVar(&t.nodes, "nodes", 1, "number of nodes, each with its own generator")
+	trials := fs.Int("trials", 1, "number of independent trials")
+	seed := fs.Uint64("seed", 0, "seed for reproducible random parts (default: cryptographically random)")
+	config := cliconfig.AddFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(e.stderr, "simulate takes no arguments, got %q\n", fs.Args())
+		return errUsage
+	}
+	if err := t.validate(); err != nil {
+		return err
+	}
+	if *trials < 1 {
+		return errors.New("there must be at least one trial")
+	}
+
+	s, err := config.Resolve()
+	if err != nil {
+		return err
+	}
+
+	result := simulation{lengths: make(map[int]int), trials: *trials, profile: t}
+	start := e.now().UTC()
+	for trial := 0; trial < *trials; trial++ {
+		err := simulateTrial(s, t, start, randomSources(*seed, trial, t.nodes), &result)
+		if err != nil {
+			return err
+		}
+	}
+	result.print(e.stdout)
+	return nil
+}
+
+// validate ensures the traffic profile makes sense.
+func (t traffic) validate() error {
+	switch {
+	case t.rate < 0:
+		return errors.New("rate cannot be negative")
+	case t.burst < 0:
+		return errors.New("burst cannot be negative")
+	case t.burst > 0 && t.burstEvery <= 0:
+		return errors.New("burst interval must be positive")
+	case t.duration <= 0:
+		return errors.New("duration must be positive")
+	case t.nodes < 1:
+		return errors.New("there must be at least one node")
+	}
+	return nil
+}
+
+// randomSources returns a random source for each node in the trial, seeded from the seed if it's non-zero.
+func randomSources(seed uint64, trial, nodes int) []io.Reader {
+	sources := make([]io.Reader, nodes)
+	for node := range sources {
+		if seed == 0 {
+			sources[node] = crand.Reader
+			continue
+		}
+		var chachaSeed [32]byte
+		binary.LittleEndian.PutUint64(chachaSeed[0:], seed)
+		binary.LittleEndian.PutUint64(chachaSeed[8:], uint64(trial))
+		binary.LittleEndian.PutUint64(chachaSeed[16:], uint64(node))
+		sources[node] = rand.NewChaCha8(chachaSeed)
+	}
+	return sources
+}
+
+// simulateTrial generates the profile's IDs on each node, from the start time, adding to the result.
+func simulateTrial(s cliconfig.Settings, t traffic, start time.Time, sources []io.Reader, result *simulation) error {
+	seen := make(map[string]struct{})
+	perTick := make(map[int64]int)
+	collisions := 0
+
+	for _, source := range sources {
+		now := start
+		gen, err := flexid.NewGenerator(s.Config().
+			WithRandomSource(source).
+			WithTimeProvider(func() time.Time { return now }))
+		if err != nil {
+			return err
+		}
+
+		for _, at := range t.schedule() {
+			now = start.Add(at)
+			id, err := gen.Generate()
+			if err != nil {
+				return err
+			}
+
+			if _, ok := seen[id]; ok {
+				collisions++
+			}
+			seen[id] = struct{}{}
+			tick := int64(0)
+			if s.Tick > 0 {
+				tick = int64(now.Sub(s.Epoch) / s.Tick)
+			}
+			perTick[tick]++
+			result.lengths[len(id)]++
+			result.ids++
+		}
+	}
+
+	result.collisions += collisions
+	if collisions > 0 {
+		result.collidingTrials++
+	}
+	for _, n := range perTick {
+		result.maxPerTick = max(result.maxPerTick, n)
+	}
+	return nil
+}
+
+// schedule returns the offsets from the start at which a node generates IDs, in order: steady traffic
+// evenly spaced at the rate, plus bursts at each interval, starting immediately.
+func (t traffic) schedule() []time.Duration {
+	var steady []time.Duration
+	if t.rate > 0 {
+		count := int(t.rate * t.duration.Seconds())
+		steady = make([]time.Duration, count)
+		for i := range steady {
+			steady[i] = time.Duration(float64(i) / t.rate * float64(time.Second))
+		}
+	}
+
+	var bursts []time.Duration
+	if t.burst > 0 {
+		for at := time.Duration(0); at < t.duration; at += t.burstEvery {
+			for i := 0; i < t.burst; i++ {
+				bursts = append(bursts, at)
+			}
+		}
+	}
+
+	// Merge the two, which are each already in order.
+	merged := make([]time.Duration, 0, len(steady)+len(bursts))
+	for len(steady) > 0 && len(bursts) > 0 {
+		if bursts[0] <= steady[0] {
+			merged = append(merged, bursts[0])
+			bursts = bursts[1:]
+		} else {
+			merged = append(merged, steady[0])
+			steady = steady[1:]
+		}
+	}
+	merged = append(merged, steady...)
+	return append(merged, bursts...)
+}
+
+// print reports the simulation's outcome.
+func (r simulation) print(w io.Writer) {
+	t := r.profile
+	fmt.Fprintf(w, "simulated:    %d IDs over %s from %d node(s), in %d trial(s)\n", r.ids, t.duration, t.nodes, r.trials)
+	fmt.Fprintf(w, "collisions:   %d (%d of %d trials had any)\n", r.collisions, r.collidingTrials, r.trials)
+	fmt.Fprintf(w, "busiest tick: %d IDs\n", r.maxPerTick)
+	fmt.Fprintln(w, "lengths:")
+
+	lengths := make([]int, 0, len(r.lengths))
+	for length := range r.lengths {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+	for _, length := range lengths {
+		count := r.lengths[length]
+		fmt.Fprintf(w, "  %3d: %d (%.1f%%)\n", length, count, 100*float64(count)/float64(r.ids))
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Simulate(t *testing.T) {
+	// Ticks 15 and 16 since the epoch, which is aligned with testNow: the time component grows from 1 to 2
+	// hex characters.
+	stdout, stderr, code := runCLI(t, "", "simulate", "-alphabet", "base16", "-random", "2", "-tick", "1s",
+		"-epoch", "2025-10-16T11:59:45.1Z", "-rate", "10", "-duration", "2s", "-seed", "1")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	for _, expected := range []string{
+		"simulated:    20 IDs over 2s from 1 node(s), in 1 trial(s)\n",
+		"busiest tick: 10 IDs\n",
+		"    3: 10 (50.0%)\n",
+		"    4: 10 (50.0%)\n",
+	} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, stdout)
+		}
+	}
+}
+
+func Test_Simulate_Collisions(t *testing.T) {
+	// 100 IDs per tick and node, with only 16 possible random parts, must collide.
+	args := []string{"simulate", "-alphabet", "base16", "-random", "1", "-tick", "1s", "-epoch", "2025-01-01T00:00:00.1Z", "-rate", "100",
+		"-duration", "1s", "-nodes", "2", "-trials", "3", "-seed", "7"}
+	stdout, _, code := runCLI(t, "", args...)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if !strings.Contains(stdout, "(3 of 3 trials had any)") || !strings.Contains(stdout, "busiest tick: 200 IDs") {
+		t.Errorf("Unexpected output:\n%s", stdout)
+	}
+
+	// Seeded simulations are reproducible.
+	again, _, _ := runCLI(t, "", args...)
+	if again != stdout {
+		t.Errorf("Expected the same output with the same seed, got:\n%s\nthen:\n%s", stdout, again)
+	}
+}
+
+func Test_Simulate_NoCollisions(t *testing.T) {
+	stdout, _, _ := runCLI(t, "", "simulate", "-random", "8", "-rate", "1000", "-duration", "1s", "-nodes", "3")
+	if !strings.Contains(stdout, "collisions:   0 (0 of 1 trials had any)") {
+		t.Errorf("Expected no collisions, got:\n%s", stdout)
+	}
+}
+
+func Test_Simulate_Errors(t *testing.T) {
+	testCases := [][]string{
+		{"simulate", "-rate", "-1"},
+		{"simulate", "-burst", "-1"},
+		{"simulate", "-burst", "5", "-burst-every", "0s"},
+		{"simulate", "-duration", "0s"},
+		{"simulate", "-nodes", "0"},
+		{"simulate", "-trials", "0"},
+		{"simulate", "extra"},
+	}
+	for _, args := range testCases {
+		if _, _, code := runCLI(t, "", args...); code == 0 {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
+
+func Test_Traffic_Schedule(t *testing.T) {
+	profile := traffic{rate: 2, burst: 2, burstEvery: time.Second, duration: 2 * time.Second, nodes: 1}
+	ms := time.Millisecond
+	expected := []time.Duration{0, 0, 0, 500 * ms, 1000 * ms, 1000 * ms, 1000 * ms, 1500 * ms}
+	if schedule := profile.schedule(); !slices.Equal(schedule, expected) {
+		t.Errorf("Expected schedule %v, got %v", expected, schedule)
+	}
+}
@@ -0,0 +1,150 @@
+// Command flexidd serves FlexIDs over HTTP, for services which can't embed the library.
+//
+// Usage:
+//
+//	flexidd [flags]
+//
+// Endpoints:
+//
+//	GET /id            a single ID
+//	GET /ids?n=100     n IDs, one per line
+//	GET /decode/{id}   an ID's time, tick and random part
+//	GET /config        the config, so clients can generate or parse compatible IDs
+//
+// Responses are plain text, or JSON given ?format=json or an Accept header of application/json.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// shutdownTimeout is how long in-flight requests are given to finish once the server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// run parses the flags and serves until ctx is done, returning the process's exit code.
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("flexidd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "TCP address to listen on")
+	socket := fs.String("socket", "", "Unix socket to listen on instead of -addr")
+	maxBatch := fs.Int("max-batch", 10000, "maximum number of IDs served by a single /ids request")
+	config := cliconfig.AddFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: flexidd [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "flexidd takes no arguments, got %q\n", fs.Args())
+		return 2
+	}
+
+	srv, err := newServer(config, *maxBatch)
+	if err != nil {
+		fmt.Fprintf(stderr, "flexidd: %v\n", err)
+		return 1
+	}
+
+	listener, err := listen(*addr, *socket)
+	if err != nil {
+		fmt.Fprintf(stderr, "flexidd: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "flexidd: listening on %s\n", listener.Addr())
+
+	if err := serve(ctx, listener, srv.handler()); err != nil {
+		fmt.Fprintf(stderr, "flexidd: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newServer returns a server for the config flags.
+func newServer(flags *cliconfig.Flags, maxBatch int) (*server, error) {
+	if maxBatch < 1 {
+		return nil, errors.New("max-batch must be positive")
+	}
+	s, err := flags.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	gen, err := flexid.NewGenerator(s.Config())
+	if err != nil {
+		return nil, err
+	}
+	return &server{gen: gen, settings: s, maxBatch: maxBatch}, nil
+}
+
+// listen listens on the Unix socket if one is given, otherwise on the TCP address. A stale socket file
+// left behind by a previous run is removed first, but any other file at the path is left alone.
+func listen(addr, socket string) (net.Listener, error) {
+	if socket == "" {
+		return net.Listen("tcp", addr)
+	}
+	info, err := os.Lstat(socket)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case info.Mode()&os.ModeSocket == 0:
+		return nil, fmt.Errorf("%s already exists and is not a socket", socket)
+	default:
+		if err := os.Remove(socket); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", socket)
+}
+
+// serve serves the handler on the listener until ctx is done, then shuts down gracefully, giving in-flight
+// requests up to shutdownTimeout to finish.
+func serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Run_FlagErrors(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(context.Background(), []string{"-bogus"}, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run(context.Background(), []string{"-h"}, &stderr); code != 0 ||
+		!strings.Contains(stderr.String(), "Usage: flexidd") {
+		t.Errorf("Expected help and exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run(context.Background(), []string{"-alphabet", "aa"}, &stderr); code != 1 ||
+		!strings.HasPrefix(stderr.String(), "flexidd: ") {
+		t.Errorf("Expected a config error and exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run(context.Background(), []string{"-max-batch", "0"}, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for a non-positive max batch, got %d: %s", code, stderr.String())
+	}
+}
+
+func Test_Run_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "flexidd.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	codes := make(chan int, 1)
+	var stderr bytes.Buffer
+	go func() {
+		codes <- run(ctx, []string{"-socket", socket, "-random", "3"}, &stderr)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	var resp *http.Response
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if resp, err = client.Get("http://flexidd/id"); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("Server never became reachable: %v", err)
+	}
+	var body bytes.Buffer
+	_, _ = body.ReadFrom(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(strings.TrimSpace(body.String())) < 4 {
+		t.Errorf("Expected an ID, got %d: %s", resp.StatusCode, body.String())
+	}
+
+	cancel()
+	select {
+	case code := <-codes:
+		if code != 0 {
+			t.Errorf("Expected a clean shutdown, got exit code %d", code)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("Server didn't shut down")
+	}
+}
+
+func Test_Listen_StaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "flexidd.sock")
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := listen("", socket)
+	if err != nil {
+		t.Fatalf("Expected the stale socket to be replaced, got %v", err)
+	}
+	listener.Close()
+}
+
+func Test_Listen_NotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "important.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if listener, err := listen("", path); err == nil {
+		listener.Close()
+		t.Fatal("Expected an error listening on a regular file's path, but got nil")
+	}
+	if content, err := os.ReadFile(path); err != nil || string(content) != "keep me" {
+		t.Errorf("Expected the file to be left alone, got %q, %v", content, err)
+	}
+}
+
+func Test_Serve_FinishesInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- serve(ctx, listener, handler)
+	}()
+
+	bodies := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			bodies <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(resp.Body)
+		bodies <- body.String()
+	}()
+
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if body := <-bodies; body != "done" {
+		t.Errorf("Expected the in-flight request to finish, got %q", body)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// server serves IDs from a generator over HTTP.
+type server struct {
+	gen      *flexid.Generator
+	settings cliconfig.Settings
+	maxBatch int
+}
+
+// handler returns the server's routes.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /id", s.handleID)
+	mux.HandleFunc("GET /ids", s.handleIDs)
+	mux.HandleFunc("GET /decode/{id}", s.handleDecode)
+	mux.HandleFunc("GET /config", s.handleConfig)
+	return mux
+}
+
+// handleID generates a single ID.
+func (s *server) handleID(w http.ResponseWriter, r *http.Request) {
+	id, err := s.gen.Generate()
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, map[string]string{"id": id})
+		return
+	}
+	writeText(w, http.StatusOK, id)
+}
+
+// handleIDs generates n IDs, one per line in plain text.
+func (s *server) handleIDs(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if value := r.URL.Query().Get("n"); value != "" {
+		var err error
+		n, err = strconv.Atoi(value)
+		if err != nil || n < 1 || n > s.maxBatch {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("n must be between 1 and %d", s.maxBatch))
+			return
+		}
+	}
+
+	ids := make([]string, n)
+	for i := range ids {
+		var err error
+		ids[i], err = s.gen.Generate()
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, map[string][]string{"ids": ids})
+		return
+	}
+	writeText(w, http.StatusOK, strings.Join(ids, "\n"))
+}
+
+// decodedID is an ID's decoded components.
+type decodedID struct {
+	ID     string     `json:"id"`
+	Time   *time.Time `json:"time,omitempty"`
+	Tick   uint64     `json:"tick"`
+	Random string     `json:"random"`
+}
+
+// handleDecode decodes an ID generated with the server's config.
+func (s *server) handleDecode(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	parsed, err := s.gen.Parse(id)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decoded := decodedID{ID: id, Tick: parsed.Ticks, Random: parsed.Random}
+	if !parsed.Time.IsZero() {
+		t := parsed.Time.UTC()
+		decoded.Time = &t
+	}
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, decoded)
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "id: %s\n", decoded.ID)
+	if decoded.Time != nil {
+		fmt.Fprintf(&sb, "time: %s\ntick: %d\n", decoded.Time.Format(time.RFC3339Nano), decoded.Tick)
+	}
+	fmt.Fprintf(&sb, "random: %s", decoded.Random)
+	writeText(w, http.StatusOK, sb.String())
+}
+
+// configResponse describes the server's config, so clients can generate or parse compatible IDs.
+type configResponse struct {
+	Alphabet    string  `json:"alphabet"`
+	TickSize    string  `json:"tickSize"`
+	Epoch       string  `json:"epoch"`
+	RandomChars int     `json:"randomChars"`
+	EntropyBits float64 `json:"entropyBits"`
+}
+
+// handleConfig describes the server's config.
+func (s *server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	config := configResponse{
+		Alphabet:    s.settings.Alphabet,
+		TickSize:    s.settings.Tick.String(),
+		Epoch:       s.settings.Epoch.Format(time.RFC3339Nano),
+		RandomChars: s.settings.Random,
+		EntropyBits: s.gen.EntropyBits(),
+	}
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, config)
+		return
+	}
+	writeText(w, http.StatusOK, fmt.Sprintf("alphabet: %s\ntickSize: %s\nepoch: %s\nrandomChars: %d\nentropyBits: %.1f",
+		config.Alphabet, config.TickSize, config.Epoch, config.RandomChars, config.EntropyBits))
+}
+
+// wantsJSON reports whether the client asked for JSON, via ?format=json or the Accept header.
+// Responses are plain text otherwise.
+func wantsJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "json"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeText(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintln(w, body)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if wantsJSON(r) {
+		writeJSON(w, status, map[string]string{"error": message})
+		return
+	}
+	writeText(w, status, message)
+}
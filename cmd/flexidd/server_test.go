@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amterp/flexid"
+	"github.com/amterp/flexid/internal/cliconfig"
+)
+
+// testNow is the fixed current time for server tests.
+var testNow = time.Date(2025, 10, 16, 12, 0, 0, 100_000_000, time.UTC)
+
+// newTestServer returns a server with 100ms ticks and 2 random characters, whose clock is fixed at testNow.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	settings := cliconfig.Settings{Alphabet: flexid.Base62Alphabet, Tick: 100 * time.Millisecond, Random: 2,
+		Epoch: time.Unix(0, 0).UTC()}
+	gen, err := flexid.NewGenerator(settings.Config().WithTimeProvider(func() time.Time { return testNow }))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	srv := httptest.NewServer((&server{gen: gen, settings: settings, maxBatch: 100}).handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get requests the path, returning the response's status, content type and body.
+func get(t *testing.T, srv *httptest.Server, path, accept string) (int, string, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp.StatusCode, resp.Header.Get("Content-Type"), body.String()
+}
+
+func Test_Server_ID(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, contentType, body := get(t, srv, "/id", "")
+	if status != http.StatusOK || !strings.HasPrefix(contentType, "text/plain") {
+		t.Fatalf("Expected 200 text/plain, got %d %s: %s", status, contentType, body)
+	}
+	if id := strings.TrimSpace(body); len(id) != 8 || !strings.HasPrefix(id, "JDVeUb") {
+		t.Errorf("Expected an ID for testNow, got %q", id)
+	}
+
+	status, contentType, body = get(t, srv, "/id", "application/json")
+	if status != http.StatusOK || contentType != "application/json" {
+		t.Fatalf("Expected 200 application/json, got %d %s: %s", status, contentType, body)
+	}
+	var resp struct{ ID string }
+	if err := json.Unmarshal([]byte(body), &resp); err != nil || len(resp.ID) != 8 {
+		t.Errorf("Expected a JSON ID, got %q: %v", body, err)
+	}
+}
+
+func Test_Server_IDs(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, _, body := get(t, srv, "/ids?n=5", "")
+	if status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", status, body)
+	}
+	if ids := strings.Fields(body); len(ids) != 5 {
+		t.Errorf("Expected 5 IDs, got %q", body)
+	}
+
+	status, _, body = get(t, srv, "/ids?n=3&format=json", "")
+	var resp struct{ IDs []string }
+	if status != http.StatusOK || json.Unmarshal([]byte(body), &resp) != nil || len(resp.IDs) != 3 {
+		t.Errorf("Expected 3 IDs as JSON, got %d: %s", status, body)
+	}
+
+	status, _, body = get(t, srv, "/ids", "")
+	if status != http.StatusOK || len(strings.Fields(body)) != 1 {
+		t.Errorf("Expected a single ID by default, got %d: %s", status, body)
+	}
+}
+
+func Test_Server_IDs_InvalidCount(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, n := range []string{"0", "-1", "101", "many"} {
+		status, _, body := get(t, srv, "/ids?n="+n+"&format=json", "")
+		if status != http.StatusBadRequest || !strings.Contains(body, `"error":"n must be between 1 and 100"`) {
+			t.Errorf("n=%s: expected a 400 JSON error, got %d: %s", n, status, body)
+		}
+	}
+}
+
+func Test_Server_Decode(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, _, body := get(t, srv, "/decode/J2mCmTab", "")
+	expected := "id: J2mCmTab\ntime: 2025-04-15T22:35:35.7Z\ntick: 17447565357\nrandom: ab\n"
+	if status != http.StatusOK || body != expected {
+		t.Errorf("Expected 200 with:\n%s\nGot %d:\n%s", expected, status, body)
+	}
+
+	status, _, body = get(t, srv, "/decode/J2mCmTab", "application/json")
+	var d decodedID
+	if status != http.StatusOK || json.Unmarshal([]byte(body), &d) != nil {
+		t.Fatalf("Expected 200 with JSON, got %d: %s", status, body)
+	}
+	if d.Tick != 17447565357 || d.Random != "ab" || d.Time == nil ||
+		!d.Time.Equal(time.Date(2025, 4, 15, 22, 35, 35, 700_000_000, time.UTC)) {
+		t.Errorf("Unexpected decoded ID: %+v", d)
+	}
+
+	status, _, body = get(t, srv, "/decode/J2mC!Tab", "")
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid ID, got %d: %s", status, body)
+	}
+}
+
+func Test_Server_Config(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, _, body := get(t, srv, "/config?format=json", "")
+	var config configResponse
+	if status != http.StatusOK || json.Unmarshal([]byte(body), &config) != nil {
+		t.Fatalf("Expected 200 with JSON, got %d: %s", status, body)
+	}
+	expected := configResponse{Alphabet: flexid.Base62Alphabet, TickSize: "100ms", Epoch: "1970-01-01T00:00:00Z",
+		RandomChars: 2, EntropyBits: config.EntropyBits}
+	if config != expected || config.EntropyBits < 11.9 || config.EntropyBits > 12 {
+		t.Errorf("Expected %+v, got %+v", expected, config)
+	}
+
+	status, _, body = get(t, srv, "/config", "")
+	if status != http.StatusOK || !strings.Contains(body, "tickSize: 100ms\n") {
+		t.Errorf("Expected the config as text, got %d: %s", status, body)
+	}
+}
+
+func Test_Server_MethodNotAllowed(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := srv.Client().Post(srv.URL+"/id", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,394 @@
+package flexid
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrPrecisionLoss is wrapped by the error from a conversion which can't represent an ID's time exactly, e.g.
+// a ULID's milliseconds in a generator with 1s ticks.
+var ErrPrecisionLoss = errors.New("time precision would be lost")
+
+// ErrEntropyLoss is wrapped by the error from a conversion which can't represent an ID's random part, e.g. a
+// ULID's 80 random bits in 5 Base62 characters.
+var ErrEntropyLoss = errors.New("entropy would be lost")
+
+// Epochs of common Snowflake variants, for FromSnowflake and ToSnowflake.
+var (
+	TwitterSnowflakeEpoch = time.UnixMilli(1288834974657).UTC()
+	DiscordSnowflakeEpoch = time.UnixMilli(1420070400000).UTC()
+)
+
+const (
+	crockfordBase32Lower = "0123456789abcdefghjkmnpqrstvwxyz"
+	ulidLen              = 26
+	ulidTimeBits         = 48
+	ulidRandomBits       = 80
+	ksuidLen             = 27
+	ksuidTimeBits        = 32
+	ksuidPayloadBits     = 128
+	snowflakeTimeBits    = 41
+	snowflakeOtherBits   = 22 // Worker and sequence bits, carried in place of a random part.
+)
+
+// ksuidEpoch is the epoch of KSUID timestamps.
+var ksuidEpoch = time.Unix(1400000000, 0).UTC()
+
+// components are an ID's time and random part, independent of its format.
+type components struct {
+	time   time.Time // Zero if the ID has no time component.
+	random *big.Int  // The random part as a number.
+}
+
+// FromULID converts a ULID to an ID for the generator, keeping its time and random bits. The generator must
+// use the default layout, without a word list or the pronounceable random strategy, or be a UUIDv7 generator.
+// Converted IDs aren't checked against the blocklist, deduplication or registry.
+//
+// If the generator's ticks can't represent the ULID's millisecond exactly, or its random part can't hold the
+// ULID's 80 random bits (e.g. WithNumRandomChars(14) is needed for Base62), the closest ID is returned along
+// with an error wrapping ErrPrecisionLoss or ErrEntropyLoss, leaving callers to decide whether to accept it.
+// The time is truncated to its tick, and the random bits are reduced to their lowest-order digits.
+func (g *Generator) FromULID(ulid string) (string, error) {
+	value, err := decodeFixed(ulid, ulidLen, crockfordBase32Lower, true)
+	if err != nil {
+		return "", fmt.Errorf("invalid ULID %q: %w", ulid, err)
+	}
+	if value.BitLen() > 128 {
+		return "", fmt.Errorf("invalid ULID %q: number overflows 128 bits", ulid)
+	}
+	return g.compose(splitBits(value, ulidRandomBits, time.UnixMilli(0).UTC(), time.Millisecond))
+}
+
+// ToULID converts an ID generated with the generator's config to a ULID, in Crockford Base32. Losses are
+// reported like FromULID's.
+func (g *Generator) ToULID(id string) (string, error) {
+	c, err := g.decompose(id)
+	if err != nil {
+		return "", err
+	}
+	var losses []error
+	value, err := joinBits(c, ulidRandomBits, ulidTimeBits, time.UnixMilli(0).UTC(), time.Millisecond, "ULID", &losses)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(encodeFixed(value, ulidLen, crockfordBase32Lower)), errors.Join(losses...)
+}
+
+// FromUUIDv7 converts a UUIDv7 in the canonical form to an ID for the generator, keeping its time and 74
+// random bits. Requirements and losses are as for FromULID.
+func (g *Generator) FromUUIDv7(uuid string) (string, error) {
+	bytes, err := parseUUIDHex(uuid)
+	if err != nil {
+		return "", err
+	}
+	if bytes[6]>>4 != 7 || bytes[8]>>6 != 2 {
+		return "", fmt.Errorf("UUID %q is not a UUIDv7", uuid)
+	}
+	return g.compose(uuidComponents(bytes))
+}
+
+// ToUUIDv7 converts an ID generated with the generator's config to a UUIDv7 in the canonical form. Losses
+// are reported like FromULID's.
+func (g *Generator) ToUUIDv7(id string) (string, error) {
+	c, err := g.decompose(id)
+	if err != nil {
+		return "", err
+	}
+	var losses []error
+	uuid, err := uuidFromComponents(c, &losses)
+	if err != nil {
+		return "", err
+	}
+	return formatUUIDHex(uuid), errors.Join(losses...)
+}
+
+// FromKSUID converts a KSUID to an ID for the generator, keeping its time, to the second, and its 128-bit
+// payload. Requirements and losses are as for FromULID.
+func (g *Generator) FromKSUID(ksuid string) (string, error) {
+	value, err := decodeFixed(ksuid, ksuidLen, Base62Alphabet, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid KSUID %q: %w", ksuid, err)
+	}
+	if value.BitLen() > ksuidTimeBits+ksuidPayloadBits {
+		return "", fmt.Errorf("invalid KSUID %q: number overflows 160 bits", ksuid)
+	}
+	return g.compose(splitBits(value, ksuidPayloadBits, ksuidEpoch, time.Second))
+}
+
+// ToKSUID converts an ID generated with the generator's config to a KSUID. Losses are reported like FromULID's.
+func (g *Generator) ToKSUID(id string) (string, error) {
+	c, err := g.decompose(id)
+	if err != nil {
+		return "", err
+	}
+	var losses []error
+	value, err := joinBits(c, ksuidPayloadBits, ksuidTimeBits, ksuidEpoch, time.Second, "KSUID", &losses)
+	if err != nil {
+		return "", err
+	}
+	return encodeFixed(value, ksuidLen, Base62Alphabet), errors.Join(losses...)
+}
+
+// FromSnowflake converts a Snowflake ID with the given epoch, such as TwitterSnowflakeEpoch, to an ID for the
+// generator. Its 41-bit millisecond timestamp becomes the time component, and its 22 worker and sequence bits
+// become the random part. Requirements and losses are as for FromULID.
+func (g *Generator) FromSnowflake(snowflake int64, epoch time.Time) (string, error) {
+	if snowflake < 0 {
+		return "", fmt.Errorf("invalid Snowflake %d: cannot be negative", snowflake)
+	}
+	return g.compose(splitBits(big.NewInt(snowflake), snowflakeOtherBits, epoch, time.Millisecond))
+}
+
+// ToSnowflake converts an ID generated with the generator's config to a Snowflake ID with the given epoch.
+// The random part becomes the worker and sequence bits. Losses are reported like FromULID's.
+func (g *Generator) ToSnowflake(id string, epoch time.Time) (int64, error) {
+	c, err := g.decompose(id)
+	if err != nil {
+		return 0, err
+	}
+	var losses []error
+	value, err := joinBits(c, snowflakeOtherBits, snowflakeTimeBits, epoch, time.Millisecond, "Snowflake", &losses)
+	if err != nil {
+		return 0, err
+	}
+	return value.Int64(), errors.Join(losses...)
+}
+
+// Transcode converts an ID generated with one generator's config to the equivalent ID for another, such as
+// when moving from Base62Alphabet to Base36Alphabet. The time is re-encoded relative to the new epoch and tick
+// size, and the random part's value is re-encoded in the new alphabet. Requirements and losses are as for
+// FromULID: losses are reported for the given ID, so an ID whose random value happens to fit transcodes
+// without error even when others wouldn't. To rule out entropy loss for every ID, give the target at least as
+// many random bits, as reported by EntropyBits.
+func Transcode(id string, from, to *Generator) (string, error) {
+	c, err := from.decompose(id)
+	if err != nil {
+		return "", err
+	}
+	return to.compose(c)
+}
+
+// checkConvertible ensures the generator's IDs consist of just a time component and a random part which
+// can hold any number, as conversions require.
+func (g *Generator) checkConvertible() error {
+	if g.segments != nil || g.config.words != nil || g.config.randomStrategy != RandomAlphabet {
+		return errors.New("conversions require the default layout, without a word list or pronounceable random part")
+	}
+	return nil
+}
+
+// randomSpace returns the number of possible random parts.
+func (g *Generator) randomSpace() *big.Int {
+	if g.config.uuidV7 {
+		return new(big.Int).Lsh(big.NewInt(1), uuidRandomBits)
+	}
+	return new(big.Int).Exp(big.NewInt(int64(g.base)), big.NewInt(int64(g.config.numRandomChars)), nil)
+}
+
+// decompose returns the components of an ID generated with the generator's config.
+func (g *Generator) decompose(id string) (components, error) {
+	if err := g.checkConvertible(); err != nil {
+		return components{}, err
+	}
+	parsed, err := g.Parse(id)
+	if err != nil {
+		return components{}, err
+	}
+
+	c := components{}
+	if g.config.tickSize > 0 {
+		c.time = parsed.Time
+	}
+	if g.config.uuidV7 {
+		var uuid [16]byte
+		if _, err := hex.Decode(uuid[6:], []byte(parsed.Random)); err != nil {
+			return components{}, err
+		}
+		c.random = uuidComponents(uuid).random
+		return c, nil
+	}
+	c.random, err = decodeFixed(parsed.Random, len(parsed.Random), g.config.alphabet, false)
+	if err != nil {
+		return components{}, err
+	}
+	return c, nil
+}
+
+// compose returns the generator's ID for the components, along with any losses.
+func (g *Generator) compose(c components) (string, error) {
+	if err := g.checkConvertible(); err != nil {
+		return "", err
+	}
+
+	var losses []error
+	if g.config.uuidV7 {
+		uuid, err := uuidFromComponents(c, &losses)
+		if err != nil {
+			return "", err
+		}
+		return g.formatUUID(uuid), errors.Join(losses...)
+	}
+
+	var sb strings.Builder
+	if g.config.tickSize > 0 {
+		if c.time.IsZero() {
+			return "", errors.New("ID has no time component to convert")
+		}
+		ticks, err := unitsSince(c.time, g.config.epoch, g.config.tickSize, 64, "the generator", &losses)
+		if err != nil {
+			return "", err
+		}
+		encoded, err := g.encodeBaseN(ticks)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(encoded)
+	} else if !c.time.IsZero() {
+		losses = append(losses, fmt.Errorf("%w: the generator has no time component", ErrPrecisionLoss))
+	}
+
+	random := fitRandom(c.random, g.randomSpace(), &losses)
+	sb.WriteString(encodeFixed(random, g.config.numRandomChars, g.config.alphabet))
+	return g.group(sb.String()), errors.Join(losses...)
+}
+
+// splitBits splits a number into a time component above the given number of random bits, counting units
+// since the epoch, and the random bits below.
+func splitBits(value *big.Int, randomBits int, epoch time.Time, unit time.Duration) components {
+	units := new(big.Int).Rsh(value, uint(randomBits))
+	random := new(big.Int).And(value, lowBitsMask(randomBits))
+
+	// Split the offset into whole seconds and nanoseconds, as time.Duration only spans 292 years.
+	nanos := units.Mul(units, big.NewInt(int64(unit)))
+	seconds, nanos := new(big.Int).QuoRem(nanos, big.NewInt(int64(time.Second)), new(big.Int))
+	t := time.Unix(epoch.Unix()+seconds.Int64(), int64(epoch.Nanosecond())+nanos.Int64()).UTC()
+	return components{time: t, random: random}
+}
+
+// joinBits is the inverse of splitBits, returning an error if the time can't be represented in timeBits.
+// The format names the target format in errors.
+func joinBits(c components, randomBits, timeBits int, epoch time.Time, unit time.Duration, format string,
+	losses *[]error) (*big.Int, error) {
+	if c.time.IsZero() {
+		return nil, fmt.Errorf("ID has no time component to convert to a %s", format)
+	}
+	units, err := unitsSince(c.time, epoch, unit, timeBits, "a "+format, losses)
+	if err != nil {
+		return nil, err
+	}
+	random := fitRandom(c.random, new(big.Int).Lsh(big.NewInt(1), uint(randomBits)), losses)
+	value := new(big.Int).Lsh(new(big.Int).SetUint64(units), uint(randomBits))
+	return value.Or(value, random), nil
+}
+
+// unitsSince returns the number of whole units from the epoch to t, returning an error if t is before the
+// epoch or the number doesn't fit in numBits. A remainder is appended to losses. The target names what the
+// units are for in errors.
+func unitsSince(t, epoch time.Time, unit time.Duration, numBits int, target string, losses *[]error) (uint64, error) {
+	if t.Before(epoch) {
+		return 0, fmt.Errorf("time %s is before the epoch of %s", t.Format(time.RFC3339Nano), target)
+	}
+	// Split the difference into whole seconds and nanoseconds, as time.Duration only spans 292 years.
+	seconds := t.Unix() - epoch.Unix()
+	nanos := int64(t.Nanosecond()) - int64(epoch.Nanosecond())
+	total := new(big.Int).Mul(big.NewInt(seconds), big.NewInt(int64(time.Second)))
+	total.Add(total, big.NewInt(nanos))
+
+	units, remainder := new(big.Int).QuoRem(total, big.NewInt(int64(unit)), new(big.Int))
+	if units.BitLen() > numBits {
+		return 0, fmt.Errorf("time %s is beyond the range of %s", t.Format(time.RFC3339Nano), target)
+	}
+	if remainder.Sign() != 0 {
+		*losses = append(*losses, fmt.Errorf("%w: %s is not a whole number of %s units since the epoch of %s",
+			ErrPrecisionLoss, t.Format(time.RFC3339Nano), unit, target))
+	}
+	return units.Uint64(), nil
+}
+
+// fitRandom returns the random part reduced to its lowest-order digits within the space, appending a loss
+// to losses if it doesn't fit.
+func fitRandom(random, space *big.Int, losses *[]error) *big.Int {
+	if random.Cmp(space) < 0 {
+		return random
+	}
+	*losses = append(*losses, fmt.Errorf("%w: the random part needs %d bits, but only %d fit",
+		ErrEntropyLoss, random.BitLen(), new(big.Int).Sub(space, big.NewInt(1)).BitLen()))
+	return new(big.Int).Mod(random, space)
+}
+
+// uuidComponents returns a UUIDv7's components. Its random part is its 74 random bits, rand_a followed by
+// rand_b, skipping the version and variant bits.
+func uuidComponents(uuid [16]byte) components {
+	millis := uint64(uuid[0])<<40 | uint64(uuid[1])<<32 | uint64(uuid[2])<<24 | uint64(uuid[3])<<16 |
+		uint64(uuid[4])<<8 | uint64(uuid[5])
+	randA := new(big.Int).SetBytes([]byte{uuid[6] & 0x0f, uuid[7]})
+	randB := new(big.Int).SetBytes(append([]byte{uuid[8] & 0x3f}, uuid[9:]...))
+	random := randA.Lsh(randA, 62)
+	return components{time: time.UnixMilli(int64(millis)).UTC(), random: random.Or(random, randB)}
+}
+
+// uuidFromComponents is the inverse of uuidComponents.
+func uuidFromComponents(c components, losses *[]error) ([16]byte, error) {
+	var uuid [16]byte
+	if c.time.IsZero() {
+		return uuid, errors.New("ID has no time component to convert to a UUIDv7")
+	}
+	millis, err := unitsSince(c.time, time.UnixMilli(0).UTC(), time.Millisecond, uuidTimeBits, "a UUIDv7", losses)
+	if err != nil {
+		return uuid, err
+	}
+
+	random := fitRandom(c.random, new(big.Int).Lsh(big.NewInt(1), uuidRandomBits), losses)
+	randA := new(big.Int).Rsh(random, 62)
+	randB := new(big.Int).And(random, lowBitsMask(62))
+	randB.FillBytes(uuid[8:])
+	randA.FillBytes(uuid[6:8])
+	putUUIDTime(&uuid, millis)
+	return uuid, nil
+}
+
+// lowBitsMask returns a number with the lowest n bits set.
+func lowBitsMask(n int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(n))
+	return mask.Sub(mask, big.NewInt(1))
+}
+
+// encodeFixed encodes the number in the alphabet, left-padded with its first character to width characters.
+// The number must fit.
+func encodeFixed(number *big.Int, width int, alphabet string) string {
+	buf := make([]byte, width)
+	base := big.NewInt(int64(len(alphabet)))
+	n, digit := new(big.Int).Set(number), new(big.Int)
+	for i := width - 1; i >= 0; i-- {
+		n.QuoRem(n, base, digit)
+		buf[i] = alphabet[digit.Int64()]
+	}
+	return string(buf)
+}
+
+// decodeFixed decodes a number of exactly width characters encoded in the alphabet, which is lowercase if
+// foldCase is set, in which case the encoding is decoded case-insensitively.
+func decodeFixed(encoded string, width int, alphabet string, foldCase bool) (*big.Int, error) {
+	if len(encoded) != width {
+		return nil, fmt.Errorf("expected %d characters, got %d", width, len(encoded))
+	}
+	if foldCase {
+		encoded = strings.ToLower(encoded)
+	}
+
+	number := new(big.Int)
+	base := big.NewInt(int64(len(alphabet)))
+	for i := 0; i < len(encoded); i++ {
+		index := strings.IndexByte(alphabet, encoded[i])
+		if index < 0 {
+			return nil, fmt.Errorf("character %q is not in the alphabet", encoded[i])
+		}
+		number.Mul(number, base)
+		number.Add(number, big.NewInt(int64(index)))
+	}
+	return number, nil
+}
@@ -0,0 +1,293 @@
+package flexid
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func Test_ULID_RoundTrip(t *testing.T) {
+	// The example from the ULID spec, with 16 Crockford characters holding its 80 random bits exactly.
+	gen := MustNewGenerator(NewConfig().WithAlphabet(CrockfordBase32Alphabet).WithNumRandomChars(16))
+
+	id, err := gen.FromULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatalf("FromULID failed: %v", err)
+	}
+	if id != "1ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Expected 1ARZ3NDEKTSV4RRFFQ69G5FAV, got %s", id)
+	}
+	if parsed := gen.MustParse(id); !parsed.Time.Equal(time.UnixMilli(1469922850259)) {
+		t.Errorf("Expected the ULID's time, got %v", parsed.Time)
+	}
+
+	ulid, err := gen.ToULID(id)
+	if err != nil || ulid != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Expected the original ULID, got %s: %v", ulid, err)
+	}
+
+	if lower, err := gen.FromULID("01arz3ndektsv4rrffq69g5fav"); lower != id || err != nil {
+		t.Errorf("Expected a lowercase ULID to convert to %s, got %s: %v", id, lower, err)
+	}
+}
+
+func Test_ULID_Losses(t *testing.T) {
+	ulid := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	gen := MustNewGenerator(NewConfig())
+	id, err := gen.FromULID(ulid)
+	if !errors.Is(err, ErrEntropyLoss) || errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected only ErrEntropyLoss, got %v", err)
+	}
+	if parsed := gen.MustParse(id); !parsed.Time.Equal(time.UnixMilli(1469922850259)) {
+		t.Errorf("Expected the lossy ID to keep the ULID's time, got %v", parsed.Time)
+	}
+
+	gen = MustNewGenerator(NewConfig().WithTickSize(Second).WithNumRandomChars(14))
+	id, err = gen.FromULID(ulid)
+	if !errors.Is(err, ErrPrecisionLoss) || errors.Is(err, ErrEntropyLoss) {
+		t.Errorf("Expected only ErrPrecisionLoss, got %v", err)
+	}
+	if parsed := gen.MustParse(id); !parsed.Time.Equal(time.Unix(1469922850, 0)) {
+		t.Errorf("Expected the time to be truncated to the second, got %v", parsed.Time)
+	}
+
+	gen = MustNewGenerator(NewConfig().WithTickSize(Second))
+	if _, err = gen.FromULID(ulid); !errors.Is(err, ErrPrecisionLoss) || !errors.Is(err, ErrEntropyLoss) {
+		t.Errorf("Expected both losses, got %v", err)
+	}
+}
+
+func Test_UUIDv7_Conversions(t *testing.T) {
+	uuid := "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+
+	uuidGen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDHex))
+	if id, err := uuidGen.FromUUIDv7(uuid); id != uuid || err != nil {
+		t.Errorf("Expected a UUIDv7 generator to keep the UUID, got %s: %v", id, err)
+	}
+
+	// 13 Base62 characters hold 77 bits, enough for the 74 random bits.
+	gen := MustNewGenerator(NewConfig().WithNumRandomChars(13))
+	id, err := gen.FromUUIDv7(uuid)
+	if err != nil {
+		t.Fatalf("FromUUIDv7 failed: %v", err)
+	}
+	if parsed := gen.MustParse(id); !parsed.Time.Equal(rfcExampleTime) {
+		t.Errorf("Expected the UUID's time, got %v", parsed.Time)
+	}
+	if back, err := gen.ToUUIDv7(id); back != uuid || err != nil {
+		t.Errorf("Expected the original UUID, got %s: %v", back, err)
+	}
+
+	if _, err := gen.FromUUIDv7("017f22e2-79b0-4cc3-98c4-dc0c0c07398f"); err == nil {
+		t.Error("Expected an error converting a UUIDv4, but got nil")
+	}
+}
+
+func Test_UUIDv7_FromULID(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDAlphabet))
+
+	id, err := gen.FromULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if !errors.Is(err, ErrEntropyLoss) {
+		t.Errorf("Expected 80 random bits not to fit in 74, got %v", err)
+	}
+	if parsed := gen.MustParse(id); !parsed.Time.Equal(time.UnixMilli(1469922850259)) {
+		t.Errorf("Expected the ULID's time, got %v", parsed.Time)
+	}
+}
+
+func Test_KSUID_RoundTrip(t *testing.T) {
+	// An example from the KSUID README, with a timestamp of 107608047 and a payload of
+	// B5A1CD34B5F99D1154FB6853345C9735. 22 Base62 characters hold its 128-bit payload.
+	ksuid := "0ujtsYcgvSTl8PAuAdqWYSMnLOv"
+	gen := MustNewGenerator(NewConfig().WithTickSize(Second).WithNumRandomChars(22))
+
+	id, err := gen.FromKSUID(ksuid)
+	if err != nil {
+		t.Fatalf("FromKSUID failed: %v", err)
+	}
+	c, err := gen.decompose(id)
+	if err != nil {
+		t.Fatalf("decompose(%q) failed: %v", id, err)
+	}
+	if expected := time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC); !c.time.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, c.time)
+	}
+	if payload, _ := new(big.Int).SetString("B5A1CD34B5F99D1154FB6853345C9735", 16); c.random.Cmp(payload) != 0 {
+		t.Errorf("Expected payload %X, got %X", payload, c.random)
+	}
+
+	if back, err := gen.ToKSUID(id); back != ksuid || err != nil {
+		t.Errorf("Expected the original KSUID, got %s: %v", back, err)
+	}
+
+	if _, err := gen.FromKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLO!"); err == nil {
+		t.Error("Expected an error converting an invalid KSUID, but got nil")
+	}
+}
+
+func Test_Snowflake_RoundTrip(t *testing.T) {
+	// The example from Discord's documentation: worker 1, process 0, increment 7.
+	const snowflake = 175928847299117063
+	gen := MustNewGenerator(NewConfig().WithNumRandomChars(4))
+
+	id, err := gen.FromSnowflake(snowflake, DiscordSnowflakeEpoch)
+	if err != nil {
+		t.Fatalf("FromSnowflake failed: %v", err)
+	}
+	c, err := gen.decompose(id)
+	if err != nil {
+		t.Fatalf("decompose(%q) failed: %v", id, err)
+	}
+	if expected := time.Date(2016, 4, 30, 11, 18, 25, 796_000_000, time.UTC); !c.time.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, c.time)
+	}
+	if expected := int64(1<<17 | 7); c.random.Int64() != expected {
+		t.Errorf("Expected worker and sequence bits %d, got %d", expected, c.random.Int64())
+	}
+
+	if back, err := gen.ToSnowflake(id, DiscordSnowflakeEpoch); back != snowflake || err != nil {
+		t.Errorf("Expected the original Snowflake, got %d: %v", back, err)
+	}
+
+	if _, err := gen.FromSnowflake(-1, DiscordSnowflakeEpoch); err == nil {
+		t.Error("Expected an error converting a negative Snowflake, but got nil")
+	}
+}
+
+func Test_Snowflake_Losses(t *testing.T) {
+	// Before Twitter's epoch.
+	gen := MustNewGenerator(NewConfig().WithNumRandomChars(4).
+		WithTimeProvider(func() time.Time { return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC) }))
+	old := gen.MustGenerate()
+	if _, err := gen.ToSnowflake(old, TwitterSnowflakeEpoch); err == nil || errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected an error for a time before the epoch, got %v", err)
+	}
+
+	// More random characters than the 22 worker and sequence bits hold.
+	wide := MustNewGenerator(NewConfig().WithRandomSource(&sameByteReader{b: 61}))
+	if _, err := wide.ToSnowflake(wide.MustGenerate(), TwitterSnowflakeEpoch); !errors.Is(err, ErrEntropyLoss) {
+		t.Errorf("Expected ErrEntropyLoss, got %v", err)
+	}
+
+	// Microsecond ticks are finer than a Snowflake's milliseconds.
+	fine := MustNewGenerator(NewConfig().WithTickSize(Microsecond).WithNumRandomChars(2).
+		WithTimeProvider(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 1500, time.UTC) }))
+	if _, err := fine.ToSnowflake(fine.MustGenerate(), TwitterSnowflakeEpoch); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected ErrPrecisionLoss, got %v", err)
+	}
+}
+
+func Test_Convert_Unsupported(t *testing.T) {
+	ulid := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Layout", NewConfig().WithLayout(MustParseLayout("x{time}{rand}"))},
+		{"Word List", NewConfig().WithWordList(Words256, "-")},
+		{"Pronounceable", NewConfig().WithRandomStrategy(RandomPronounceable)},
+		{"Before Epoch", NewConfig().WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := MustNewGenerator(tc.config).FromULID(ulid)
+			if err == nil || errors.Is(err, ErrPrecisionLoss) || errors.Is(err, ErrEntropyLoss) || id != "" {
+				t.Errorf("Expected an error other than a loss, got %q: %v", id, err)
+			}
+		})
+	}
+
+	noTime := MustNewGenerator(NewConfig().WithTickSize(0).WithNumRandomChars(14))
+	id, err := noTime.FromULID(ulid)
+	if !errors.Is(err, ErrPrecisionLoss) || len(id) != 14 {
+		t.Errorf("Expected the time to be dropped with ErrPrecisionLoss, got %q: %v", id, err)
+	}
+	if _, err := noTime.ToULID(id); err == nil {
+		t.Error("Expected an error converting an ID without a time component, but got nil")
+	}
+
+	for _, invalid := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := noTime.FromULID(invalid); err == nil {
+			t.Errorf("Expected an error converting %q, but got nil", invalid)
+		}
+	}
+}
+
+func Test_Transcode(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 123_000_000, time.UTC)
+	from := MustNewGenerator(NewConfig().
+		WithTimeProvider(func() time.Time { return now }).
+		WithRandomSource(&sameByteReader{b: 61}))
+	// Collation-safe Base62 becomes Base36 with 6 random characters, which hold 5 Base62 characters.
+	to := MustNewGenerator(NewConfig().WithCollationSafe(true))
+
+	id := from.MustGenerate()
+	transcoded, err := Transcode(id, from, to)
+	if err != nil {
+		t.Fatalf("Transcode(%q) failed: %v", id, err)
+	}
+	if parsed := to.MustParse(transcoded); !parsed.Time.Equal(now) {
+		t.Errorf("Expected %v, got %v", now, parsed.Time)
+	}
+	if back, err := Transcode(transcoded, to, from); back != id || err != nil {
+		t.Errorf("Expected to transcode %s back to %s, got %s: %v", transcoded, id, back, err)
+	}
+}
+
+func Test_Transcode_Losses(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 123_000_000, time.UTC)
+	from := MustNewGenerator(NewConfig().
+		WithTimeProvider(func() time.Time { return now }).
+		WithRandomSource(&sameByteReader{b: 61}))
+	id := from.MustGenerate()
+
+	// 5 Base36 characters can't hold "zzzzz" in Base62.
+	to := MustNewGenerator(NewConfig().WithAlphabet(Base36Alphabet))
+	transcoded, err := Transcode(id, from, to)
+	if !errors.Is(err, ErrEntropyLoss) || errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected only ErrEntropyLoss, got %v", err)
+	}
+	if parsed := to.MustParse(transcoded); !parsed.Time.Equal(now) {
+		t.Errorf("Expected the time to be kept, got %v", parsed.Time)
+	}
+
+	// Second ticks can't hold the milliseconds, but a different epoch is fine.
+	to = MustNewGenerator(NewConfig().
+		WithTickSize(Second).
+		WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).
+		WithNumRandomChars(5))
+	transcoded, err = Transcode(id, from, to)
+	if !errors.Is(err, ErrPrecisionLoss) || errors.Is(err, ErrEntropyLoss) {
+		t.Errorf("Expected only ErrPrecisionLoss, got %v", err)
+	}
+	if parsed := to.MustParse(transcoded); !parsed.Time.Equal(now.Truncate(time.Second)) {
+		t.Errorf("Expected the time to be truncated to the second, got %v", parsed.Time)
+	}
+
+	// An ID from before the target's epoch can't be transcoded at all.
+	to = MustNewGenerator(NewConfig().WithEpoch(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	if _, err := Transcode(id, from, to); err == nil || errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected an error for an ID before the epoch, got %v", err)
+	}
+
+	if _, err := Transcode("not an ID!", from, to); err == nil {
+		t.Error("Expected an error transcoding an invalid ID, but got nil")
+	}
+}
+
+func Test_Transcode_Grouping(t *testing.T) {
+	from := MustNewGenerator(NewConfig().WithGrouping("-", 4))
+	to := MustNewGenerator(NewConfig().WithAlphabet(Base16LowerAlphabet).WithNumRandomChars(8))
+
+	id := from.MustGenerate()
+	transcoded, err := Transcode(id, from, to)
+	if err != nil {
+		t.Fatalf("Transcode(%q) failed: %v", id, err)
+	}
+	if back, err := Transcode(transcoded, to, from); back != id || err != nil {
+		t.Errorf("Expected to transcode %s back to %s, got %s: %v", transcoded, id, back, err)
+	}
+}
@@ -0,0 +1,70 @@
+package flexid
+
+import "errors"
+
+// WithDedup makes the generator remember the IDs it issues within the current tick, regenerating the
+// random part of any duplicate. This guarantees IDs from a single generator are unique, even with few
+// random characters, as long as the random part has more possible values than IDs are generated per
+// tick. At most maxPerTick IDs are remembered per tick, bounding memory; once full, further IDs in the
+// tick are only checked against those remembered, and counted in Stats.Unguarded. The set resets when
+// the tick changes, so without a time component it never does.
+//
+// Duplicates are counted in Stats.Duplicates. Unlike node IDs or sequences, this does nothing for
+// uniqueness across generators. NewGenerator returns an error if IDs have no random characters.
+func (c Config) WithDedup(maxPerTick int) Config {
+	c.dedupLimit = maxPerTick
+	return c
+}
+
+// validateDedup ensures the dedup limit is usable, and that IDs have random characters to regenerate
+// duplicates with.
+func validateDedup(config Config, segments []segment) error {
+	if config.dedupLimit < 0 {
+		return errors.New("dedup limit cannot be negative")
+	}
+	if config.dedupLimit > 0 && !hasRandomChars(config, segments) {
+		return errors.New("dedup requires random characters to regenerate duplicates")
+	}
+	return nil
+}
+
+// hasRandomChars reports whether IDs generated with the config and resolved layout segments have a random part.
+func hasRandomChars(config Config, segments []segment) bool {
+	if config.uuidV7 {
+		return true
+	}
+	if segments == nil {
+		return config.numRandomChars > 0
+	}
+	for _, s := range segments {
+		if s.kind == segmentRandom {
+			return true
+		}
+	}
+	return false
+}
+
+// claimRecent reports whether the ID is new within the given tick, remembering it if there's room.
+// It always reports true if dedup is disabled.
+func (g *Generator) claimRecent(ticks uint64, id string) bool {
+	if g.config.dedupLimit == 0 {
+		return true
+	}
+
+	g.dedupMu.Lock()
+	defer g.dedupMu.Unlock()
+
+	if g.recent == nil || ticks != g.recentTick {
+		g.recent = make(map[string]struct{})
+		g.recentTick = ticks
+	}
+	if _, ok := g.recent[id]; ok {
+		return false
+	}
+	if len(g.recent) < g.config.dedupLimit {
+		g.recent[id] = struct{}{}
+	} else {
+		g.unguarded.Add(1)
+	}
+	return true
+}
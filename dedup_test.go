@@ -0,0 +1,109 @@
+package flexid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// dedupTestConfig returns a config whose IDs are the tick followed by a single binary random character,
+// with the time controlled by the returned pointer.
+func dedupTestConfig(randomBytes ...byte) (Config, *time.Duration) {
+	config, offset := fakeClockTestConfig("01", 1)
+	return config.WithRandomSource(&sequenceReader{bytes: randomBytes}), offset
+}
+
+func Test_Dedup_RetriesDuplicates(t *testing.T) {
+	config, _ := dedupTestConfig(0, 0, 1)
+	gen := MustNewGenerator(config.WithDedup(10))
+
+	first := gen.MustGenerate()
+	second := gen.MustGenerate()
+	if first == second {
+		t.Errorf("Expected distinct IDs, got %q twice", first)
+	}
+
+	stats := gen.Stats()
+	if stats.Generated != 2 || stats.Duplicates != 1 || stats.Unguarded != 0 {
+		t.Errorf("Expected 2 generated and 1 duplicate, got %+v", stats)
+	}
+}
+
+func Test_Dedup_ExhaustedTick(t *testing.T) {
+	config, _ := dedupTestConfig(0, 1, 0, 1)
+	gen := MustNewGenerator(config.WithDedup(10))
+
+	gen.MustGenerate()
+	gen.MustGenerate()
+	_, err := gen.Generate()
+	if err == nil || !strings.Contains(err.Error(), "not issued earlier in the tick") {
+		t.Errorf("Expected an error once every random part is used, got %v", err)
+	}
+	if duplicates := gen.Stats().Duplicates; duplicates != maxRandomAttempts-1 {
+		t.Errorf("Expected %d duplicates, got %d", maxRandomAttempts-1, duplicates)
+	}
+}
+
+func Test_Dedup_ResetsOnTickChange(t *testing.T) {
+	config, offset := dedupTestConfig(0)
+	gen := MustNewGenerator(config.WithDedup(10))
+
+	first := gen.MustGenerate()
+	*offset = time.Second
+	second := gen.MustGenerate()
+	if first[len(first)-1] != '0' || second[len(second)-1] != '0' {
+		t.Errorf("Expected the same random part to be reused in a new tick, got %q then %q", first, second)
+	}
+	if duplicates := gen.Stats().Duplicates; duplicates != 0 {
+		t.Errorf("Expected no duplicates, got %d", duplicates)
+	}
+}
+
+func Test_Dedup_Limit(t *testing.T) {
+	config, _ := dedupTestConfig(0, 0, 1, 1)
+	gen := MustNewGenerator(config.WithDedup(1))
+
+	ids := []string{gen.MustGenerate(), gen.MustGenerate(), gen.MustGenerate()}
+	if ids[1] == ids[0] || ids[2] != ids[1] {
+		t.Errorf("Expected only the first ID to be remembered, got %v", ids)
+	}
+	stats := gen.Stats()
+	if stats.Duplicates != 1 || stats.Unguarded != 2 {
+		t.Errorf("Expected 1 duplicate and 2 unguarded IDs, got %+v", stats)
+	}
+}
+
+func Test_Dedup_Layout(t *testing.T) {
+	config, _ := dedupTestConfig(0, 0, 1)
+	gen := MustNewGenerator(config.WithLayout(MustParseLayout("{time}-{rand:1}")).WithDedup(10))
+
+	first := gen.MustGenerate()
+	second := gen.MustGenerate()
+	if first == second || gen.Stats().Duplicates != 1 {
+		t.Errorf("Expected a duplicate to be regenerated, got %q and %q with %+v", first, second, gen.Stats())
+	}
+}
+
+func Test_Dedup_Disabled(t *testing.T) {
+	config, _ := dedupTestConfig(0)
+	gen := MustNewGenerator(config)
+
+	if first, second := gen.MustGenerate(), gen.MustGenerate(); first != second {
+		t.Errorf("Expected duplicates without dedup, got %q and %q", first, second)
+	}
+}
+
+func Test_Dedup_Validation(t *testing.T) {
+	if _, err := NewGenerator(NewConfig().WithDedup(-1)); err == nil {
+		t.Errorf("Expected an error for a negative dedup limit")
+	}
+	if _, err := NewGenerator(NewConfig().WithNumRandomChars(0).WithDedup(10)); err == nil {
+		t.Errorf("Expected an error for dedup without random characters")
+	}
+	if _, err := NewGenerator(NewConfig().WithLayout(MustParseLayout("{time}-x")).WithDedup(10)); err == nil {
+		t.Errorf("Expected an error for dedup with a layout without random segments")
+	}
+	if _, err := NewGenerator(NewConfig().WithNumRandomChars(0).WithUUIDv7(UUIDHex).WithDedup(10)); err != nil {
+		t.Errorf("Expected dedup to be allowed for UUIDv7s, got %v", err)
+	}
+}
@@ -1,13 +1,17 @@
 package flexid
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 	"math" // Import needed for the comment explanation
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // DefaultAlphabet is the standard base62 alphabet (0-9, A-Z, a-z).
@@ -44,12 +48,55 @@ type Config struct {
 	numRandomChars int              // The number of random characters to append.
 	timeProvider   func() time.Time // Function to provide the current time (for testing).
 	randomSource   io.Reader        // Source of randomness (for testing).
+	collationSafe  bool             // Whether to fold the alphabet for case-insensitive storage.
+	blocklist      []string         // Lowercased substrings which generated IDs must not contain.
+	separator      string           // Separator inserted between groups of characters, if any.
+	groupSizes     []int            // Sizes of the separated groups; the last size repeats.
+	words          []string         // Word list used as symbols instead of the alphabet, if any.
+	joiner         string           // Joiner placed between words.
+	randomStrategy RandomStrategy   // How the random part is generated.
+	layout         Layout           // Arrangement of the ID's segments, if not the default.
+	nodeID         uint64           // ID of this node, encoded in the node segment.
+	nodeWidth      int              // Number of characters in the node segment, or 0 for no node segment.
+	sequenceWidth  int              // Number of characters in the sequence segment, or 0 for no sequence segment.
+	overflowPolicy OverflowPolicy   // What to do when the sequence is exhausted within a tick.
+	tickStore      TickStore        // Persists the high-water mark, if any.
+	persistEvery   time.Duration    // How far ahead of the current time each persisted high-water mark reaches.
+	behindPolicy   BehindPolicy     // What to do while the clock is behind the persisted high-water mark.
+	maxDrift       time.Duration    // How far ahead of the clock observed IDs may be, or 0 for no limit.
+	hybridClock    bool             // Whether the sequence is a hybrid logical clock's counter.
+	hostSequence   *HostSequence    // Shares the sequence with other processes on the host, if set.
+	dedupLimit     int              // Most IDs remembered per tick to avoid duplicates, or 0 to disable.
+	registry       Registry         // Records claimed IDs for uniqueness across generators, if set.
+	uuidV7         bool             // Whether IDs are UUIDv7s.
+	uuidFormat     UUIDFormat       // How UUIDv7s are rendered.
 }
 
 // Generator is responsible for generating TIDs based on a fixed configuration.
 type Generator struct {
-	config Config
-	base   int // Cache the base (length of alphabet)
+	config  Config
+	base    int        // Cache the base (length of alphabet or word list)
+	indexes [256]int16 // Index of each byte in the alphabet, or -1 if absent (for decoding).
+
+	wordIndexes map[string]int // Index of each word in the word list (for decoding).
+	segments    []segment      // Resolved layout segments, or nil for the default layout.
+
+	mu          sync.Mutex // Guards the sequence state below.
+	lastTick    uint64     // Tick of the most recently issued sequence number.
+	sequence    uint64     // Most recently issued sequence number within lastTick.
+	hasSequence bool       // Whether any sequence number has been issued yet.
+	floor       uint64     // Tick the clock must reach before IDs are issued, from the mark loaded at startup.
+	reserved    uint64     // Reserved tick of the most recently persisted high-water mark.
+
+	dedupMu    sync.Mutex          // Guards the recent IDs below.
+	recent     map[string]struct{} // IDs issued in recentTick, if dedup is enabled.
+	recentTick uint64              // Tick of the recent IDs.
+
+	generated   atomic.Uint64 // Number of IDs successfully generated.
+	regenerated atomic.Uint64 // Number of random parts discarded for containing a blocked word.
+	duplicates  atomic.Uint64 // Number of random parts discarded for duplicating a recent ID.
+	unguarded   atomic.Uint64 // Number of IDs issued without being remembered, as the recent IDs were full.
+	conflicts   atomic.Uint64 // Number of random parts discarded for an ID already claimed in the registry.
 }
 
 var (
@@ -117,6 +164,16 @@ func (c Config) WithTimeProvider(timeProvider func() time.Time) Config {
 	return c
 }
 
+// WithCollationSafe makes the generator safe for case-insensitive storage, such as MySQL's default
+// collations or the macOS and Windows filesystems. If the alphabet contains characters which only
+// differ by case, it is folded to lowercase, and if the random part is drawn from the alphabet, the number
+// of random characters, and the width of any layout's random segments, is increased so that it keeps at
+// least the same entropy.
+func (c Config) WithCollationSafe(collationSafe bool) Config {
+	c.collationSafe = collationSafe
+	return c
+}
+
 // NewGenerator creates a new Generator instance with the given configuration.
 // It validates the configuration upon creation.
 func NewGenerator(config Config) (*Generator, error) {
@@ -133,10 +190,83 @@ func NewGenerator(config Config) (*Generator, error) {
 		return nil, err
 	}
 
-	return &Generator{
-		config: config,
-		base:   len(config.alphabet),
-	}, nil
+	if config.collationSafe && config.words == nil {
+		folded := collationSafeAlphabet(config.alphabet)
+		if len(folded) < 2 {
+			return nil, errors.New("collation-safe alphabet must contain at least 2 characters")
+		}
+		// Pronounceable random parts are already lowercase, and don't lose entropy to the folding.
+		if config.randomStrategy == RandomAlphabet {
+			config.numRandomChars = scaleRandomChars(config.numRandomChars, len(config.alphabet), len(folded))
+			config.layout = config.layout.scaleRandomWidths(len(config.alphabet), len(folded))
+		}
+		config.alphabet = folded
+	}
+
+	err = validateGrouping(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateWordList(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRandomStrategy(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSequence(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateHighWaterMark(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateUUID(config)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := resolveLayout(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateDedup(config, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := &Generator{
+		config:   config,
+		base:     len(config.alphabet),
+		segments: segments,
+	}
+	if config.words != nil {
+		generator.base = len(config.words)
+		generator.wordIndexes = make(map[string]int, len(config.words))
+		for i, word := range config.words {
+			generator.wordIndexes[word] = i
+		}
+	}
+	for i := range generator.indexes {
+		generator.indexes[i] = -1
+	}
+	for i := 0; i < len(config.alphabet); i++ {
+		generator.indexes[config.alphabet[i]] = int16(i)
+	}
+
+	err = generator.loadHighWaterMark()
+	if err != nil {
+		return nil, err
+	}
+	return generator, nil
 }
 
 func MustNewGenerator(config Config) *Generator {
@@ -149,42 +279,88 @@ func MustNewGenerator(config Config) *Generator {
 
 // Generate creates a new short TID using the generator's configuration.
 func (g *Generator) Generate() (string, error) {
-	// 1. Calculate timestamp ticks since configured epoch
-	now := g.config.timeProvider().UTC()
+	return g.GenerateContext(context.Background())
+}
 
-	// Check if current time is before the configured epoch. This must be done
-	// here, as 'now' is only known at generation time. Allow generation at epoch time.
-	if now.Before(g.config.epoch) {
-		return "", errors.New("current time is before the configured epoch")
+// GenerateContext is Generate, passing the context on to the generator's Registry, if any.
+func (g *Generator) GenerateContext(ctx context.Context) (string, error) {
+	// 1. Calculate timestamp ticks since configured epoch
+	ticks, err := g.currentTicks()
+	if err != nil {
+		return "", err
+	}
+	ticks, err = g.checkHighWaterMark(ticks)
+	if err != nil {
+		return "", err
+	}
+	if g.segments != nil {
+		return g.generateLayout(ctx, ticks)
+	}
+	if g.config.uuidV7 {
+		return g.generateUUID(ctx, ticks)
 	}
 
 	// 2. Encode timestamp ticks (if applicable)
 	encodedTimestamp := ""
 	if g.config.tickSize > 0 {
-		delta := now.Sub(g.config.epoch)
-		ticks := uint64(delta.Nanoseconds() / int64(g.config.tickSize))
-		encoded, err := g.encodeBaseN(ticks)
+		encoded, err := g.encodeTimestamp(ticks)
 		if err != nil {
 			return "", err
 		}
 		encodedTimestamp = encoded
 	}
+	if g.config.words != nil && encodedTimestamp != "" && g.config.numRandomChars > 0 {
+		encodedTimestamp += g.config.joiner
+	}
 
-	// 3. Generate random part
+	// 3. Generate random part, regenerating it while the ID contains a blocked word or isn't unique
 	randomPart := ""
-	if g.config.numRandomChars > 0 {
-		chars, err := g.generateRandomChars(g.config.numRandomChars)
+	for attempt := 1; ; attempt++ {
+		chars, err := g.generateRandomPart(g.config.numRandomChars)
+		if err != nil {
+			return "", err
+		}
+		reason := rejectBlocked
+		if len(g.config.blocklist) == 0 ||
+			!g.containsBlockedWord(encodedTimestamp+chars, [2]int{len(encodedTimestamp), len(encodedTimestamp) + len(chars)}) {
+			reason, err = g.claimUnique(ctx, ticks, encodedTimestamp, chars)
+			if err != nil {
+				return "", err
+			}
+		}
+		if reason == accepted {
+			randomPart = chars
+			break
+		}
+		err = g.rejectRandom(attempt, reason, g.config.numRandomChars > 0)
 		if err != nil {
 			return "", err
 		}
-		randomPart = chars
 	}
 
 	// 4. Combine parts
 	var sb strings.Builder
 	sb.WriteString(encodedTimestamp)
 	sb.WriteString(randomPart)
-	return sb.String(), nil
+	g.generated.Add(1)
+	return g.group(sb.String()), nil
+}
+
+// currentTicks returns the number of ticks since the configured epoch, or 0 if there's no time component.
+func (g *Generator) currentTicks() (uint64, error) {
+	now := g.config.timeProvider().UTC()
+
+	// Check if current time is before the configured epoch. This must be done
+	// here, as 'now' is only known at generation time. Allow generation at epoch time.
+	if now.Before(g.config.epoch) {
+		return 0, errors.New("current time is before the configured epoch")
+	}
+
+	if g.config.tickSize <= 0 {
+		return 0, nil
+	}
+	delta := now.Sub(g.config.epoch)
+	return uint64(delta.Nanoseconds() / int64(g.config.tickSize)), nil
 }
 
 // Generate generates a TID using the default configuration.
@@ -212,6 +388,26 @@ func (g *Generator) MustGenerate() string {
 	return id
 }
 
+// encodeTimestamp encodes the ticks using the generator's word list if it has one, or its alphabet otherwise.
+func (g *Generator) encodeTimestamp(ticks uint64) (string, error) {
+	if g.config.words != nil {
+		return g.encodeWords(ticks), nil
+	}
+	return g.encodeBaseN(ticks)
+}
+
+// generateRandomPart generates a random part of the given length using the generator's word list if it
+// has one, or its random strategy otherwise.
+func (g *Generator) generateRandomPart(length int) (string, error) {
+	if g.config.words != nil {
+		return g.generateRandomWords(length)
+	}
+	if g.config.randomStrategy == RandomPronounceable {
+		return g.generatePronounceable(length)
+	}
+	return g.generateRandomChars(length)
+}
+
 // encodeBaseN encodes a non-negative integer using the generator's alphabet.
 func (g *Generator) encodeBaseN(number uint64) (string, error) {
 	if number == 0 {
@@ -236,6 +432,27 @@ func (g *Generator) encodeBaseN(number uint64) (string, error) {
 	return string(buf[i+1:]), nil
 }
 
+// decodeBaseN decodes a string encoded by encodeBaseN.
+func (g *Generator) decodeBaseN(encoded string) (uint64, error) {
+	if encoded == "" {
+		return 0, errors.New("cannot decode an empty string")
+	}
+
+	var number uint64
+	for i := 0; i < len(encoded); i++ {
+		index := g.indexes[encoded[i]]
+		if index < 0 {
+			return 0, fmt.Errorf("character %q is not in the alphabet", encoded[i])
+		}
+		if number > (math.MaxUint64-uint64(index))/uint64(g.base) {
+			return 0, errors.New("encoded number overflows uint64")
+		}
+		number = number*uint64(g.base) + uint64(index)
+	}
+
+	return number, nil
+}
+
 // generateRandomChars generates a cryptographically secure random string of the specified length
 // using the generator's alphabet, avoiding modulo bias via rejection sampling.
 func (g *Generator) generateRandomChars(length int) (string, error) {
@@ -278,3 +495,48 @@ func validateAlphabet(alphabet string) error {
 	}
 	return nil
 }
+
+// ValidateCollationSafe returns an error if the alphabet contains characters which are only
+// distinct when compared case-sensitively (e.g. 'a' and 'A'). IDs using such an alphabet may
+// collide and mis-sort when stored in case-insensitive columns or filesystems.
+func ValidateCollationSafe(alphabet string) error {
+	seen := make(map[rune]rune)
+	for _, ch := range alphabet {
+		folded := unicode.ToLower(ch)
+		if prev, exists := seen[folded]; exists && prev != ch {
+			return fmt.Errorf("alphabet contains case-folded duplicate characters: %c and %c", prev, ch)
+		}
+		seen[folded] = ch
+	}
+	return nil
+}
+
+// collationSafeAlphabet folds the alphabet to lowercase, dropping characters which become duplicates.
+func collationSafeAlphabet(alphabet string) string {
+	if ValidateCollationSafe(alphabet) == nil {
+		return alphabet
+	}
+
+	var sb strings.Builder
+	seen := make(map[rune]struct{})
+	for _, ch := range alphabet {
+		folded := unicode.ToLower(ch)
+		if _, exists := seen[folded]; exists {
+			continue
+		}
+		seen[folded] = struct{}{}
+		sb.WriteRune(folded)
+	}
+	return sb.String()
+}
+
+// scaleRandomChars returns the number of random characters from an alphabet of toBase characters needed
+// to keep at least the entropy of numRandomChars from an alphabet of fromBase characters.
+func scaleRandomChars(numRandomChars, fromBase, toBase int) int {
+	if fromBase == toBase {
+		return numRandomChars
+	}
+	// Small tolerance so exact multiples aren't rounded up due to float error.
+	bits := float64(numRandomChars) * math.Log2(float64(fromBase))
+	return int(math.Ceil(bits/math.Log2(float64(toBase)) - 1e-9))
+}
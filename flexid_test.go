@@ -540,6 +540,79 @@ func Test_Sandbox(t *testing.T) {
 	fmt.Printf("Generated %d unique IDs\n", len(ids))
 }
 
+func Test_ValidateCollationSafe(t *testing.T) {
+	testCases := []struct {
+		name        string
+		alphabet    string
+		expectError bool
+	}{
+		{"Base62", Base62Alphabet, true},
+		{"Base64Url", Base64UrlAlphabet, true},
+		{"Base36", Base36Alphabet, false},
+		{"Base16Upper", Base16UpperAlphabet, false},
+		{"Crockford", CrockfordBase32Alphabet, false},
+		{"Mixed Without Overlap", "abcXYZ", false},
+		{"Single Overlap", "abcA", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCollationSafe(tc.alphabet)
+			if tc.expectError && err == nil {
+				t.Errorf("Expected an error for %q, but got nil", tc.alphabet)
+			} else if !tc.expectError && err != nil {
+				t.Errorf("Expected no error for %q, but got: %v", tc.alphabet, err)
+			}
+		})
+	}
+}
+
+func Test_CollationSafe(t *testing.T) {
+	testCases := []struct {
+		name             string
+		alphabet         string
+		numRandomChars   int
+		expectedAlphabet string
+		expectedRandom   int
+	}{
+		{"Base62 Folds To Base36", Base62Alphabet, 5, Base36Alphabet, 6},
+		{"Base62 Zero Random", Base62Alphabet, 0, Base36Alphabet, 0},
+		{"Base36 Unchanged", Base36Alphabet, 5, Base36Alphabet, 5},
+		{"Base64Url Folds", Base64UrlAlphabet, 4, "abcdefghijklmnopqrstuvwxyz0123456789-_", 5},
+		{"Exact Multiple", "aAbB", 3, "ab", 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gen, err := NewGenerator(NewConfig().
+				WithAlphabet(tc.alphabet).
+				WithNumRandomChars(tc.numRandomChars).
+				WithCollationSafe(true))
+			if err != nil {
+				t.Fatalf("NewGenerator failed: %v", err)
+			}
+			if gen.config.alphabet != tc.expectedAlphabet {
+				t.Errorf("alphabet got %q, want %q", gen.config.alphabet, tc.expectedAlphabet)
+			}
+			if gen.config.numRandomChars != tc.expectedRandom {
+				t.Errorf("numRandomChars got %d, want %d", gen.config.numRandomChars, tc.expectedRandom)
+			}
+
+			id := gen.MustGenerate()
+			if strings.ToLower(id) != id {
+				t.Errorf("Expected collation-safe ID to be lowercase, got %q", id)
+			}
+		})
+	}
+}
+
+func Test_CollationSafe_TooFewCharacters(t *testing.T) {
+	_, err := NewGenerator(NewConfig().WithAlphabet("aA").WithCollationSafe(true))
+	if err == nil {
+		t.Error("Expected error when folded alphabet is too small, but got nil")
+	}
+}
+
 func containsOnly(s string, alphabet string) bool {
 	for _, r := range s {
 		if !strings.ContainsRune(alphabet, r) {
@@ -560,3 +633,41 @@ func (r *sameByteReader) Read(p []byte) (int, error) {
 	}
 	return len(p), nil
 }
+
+// sequenceReader is an io.Reader that returns the given bytes in order, repeating the last one once exhausted.
+type sequenceReader struct {
+	bytes []byte
+	pos   int
+}
+
+func (r *sequenceReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.bytes[r.pos]
+		if r.pos < len(r.bytes)-1 {
+			r.pos++
+		}
+	}
+	return len(p), nil
+}
+
+// fakeClockEpoch is the epoch of configs with a fake clock.
+var fakeClockEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// withFakeClock sets the config's epoch to fakeClockEpoch, and its time to the epoch plus the offset, so
+// tests can move the clock by changing the offset.
+func withFakeClock(config Config, offset *time.Duration) Config {
+	return config.
+		WithEpoch(fakeClockEpoch).
+		WithTimeProvider(func() time.Time { return fakeClockEpoch.Add(*offset) })
+}
+
+// fakeClockTestConfig returns a config with a fake clock controlled by the returned offset, one-second
+// ticks, and the given alphabet and number of random characters, to which tests add what they cover.
+func fakeClockTestConfig(alphabet string, numRandomChars int) (Config, *time.Duration) {
+	offset := new(time.Duration)
+	config := withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithAlphabet(alphabet).
+		WithNumRandomChars(numRandomChars), offset)
+	return config, offset
+}
@@ -0,0 +1,67 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithGrouping splits generated IDs into groups joined by the separator, for IDs which people read or type,
+// e.g. "9YGD-BTxk-2Pq". Groups are filled from the left using the given sizes, with the last size repeating,
+// so a single size gives evenly sized groups. Separators are stripped again by Parse.
+func (c Config) WithGrouping(separator string, groupSizes ...int) Config {
+	c.separator = separator
+	c.groupSizes = groupSizes
+	return c
+}
+
+// validateGrouping ensures the separator can't be confused with the alphabet, and that group sizes are usable.
+func validateGrouping(config Config) error {
+	if config.separator == "" && len(config.groupSizes) == 0 {
+		return nil
+	}
+
+	if config.separator == "" {
+		return errors.New("separator cannot be empty when grouping")
+	}
+	if strings.ContainsAny(config.separator, config.alphabet) {
+		return fmt.Errorf("separator %q contains characters from the alphabet", config.separator)
+	}
+
+	if len(config.groupSizes) == 0 {
+		return errors.New("at least one group size is required when grouping")
+	}
+	for _, size := range config.groupSizes {
+		if size <= 0 {
+			return fmt.Errorf("group sizes must be positive, got %d", size)
+		}
+	}
+	return nil
+}
+
+// group inserts the configured separator between groups of the ID's characters.
+func (g *Generator) group(id string) string {
+	if g.config.separator == "" {
+		return id
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(id) + len(id)*len(g.config.separator))
+	for i, group := 0, 0; i < len(id); group++ {
+		size := g.config.groupSizes[min(group, len(g.config.groupSizes)-1)]
+		if i > 0 {
+			sb.WriteString(g.config.separator)
+		}
+		sb.WriteString(id[i:min(i+size, len(id))])
+		i += size
+	}
+	return sb.String()
+}
+
+// ungroup removes any separators from the ID.
+func (g *Generator) ungroup(id string) string {
+	if g.config.separator == "" {
+		return id
+	}
+	return strings.ReplaceAll(id, g.config.separator, "")
+}
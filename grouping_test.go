@@ -0,0 +1,65 @@
+package flexid
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Grouping(t *testing.T) {
+	testCases := []struct {
+		name       string
+		separator  string
+		groupSizes []int
+		expected   string
+	}{
+		{"Even Groups", "-", []int{4}, "1000-zzzz-z"},
+		{"Pattern", "-", []int{2, 3}, "10-00z-zzz-z"},
+		{"Multi-Char Separator", " . ", []int{5}, "1000z . zzzz"},
+		{"Group Larger Than ID", "-", []int{20}, "1000zzzzz"},
+	}
+
+	offset := 36 * 36 * 36 * time.Second
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := MustNewGenerator(withFakeClock(NewConfig().
+				WithTickSize(Second).
+				WithAlphabet(Base36Alphabet).
+				WithRandomSource(&sameByteReader{b: 35}).
+				WithGrouping(tc.separator, tc.groupSizes...), &offset))
+
+			id := gen.MustGenerate()
+			if id != tc.expected {
+				t.Errorf("Expected grouped ID %q, got %q", tc.expected, id)
+			}
+
+			parsed, err := gen.Parse(id)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", id, err)
+			}
+			if parsed.Ticks != 36*36*36 || parsed.Random != "zzzzz" {
+				t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+			}
+		})
+	}
+}
+
+func Test_Grouping_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Empty Separator", NewConfig().WithGrouping("", 4)},
+		{"No Group Sizes", NewConfig().WithGrouping("-")},
+		{"Zero Group Size", NewConfig().WithGrouping("-", 4, 0)},
+		{"Separator In Alphabet", NewConfig().WithAlphabet(Base64UrlAlphabet).WithGrouping("-", 4)},
+		{"Separator Partially In Alphabet", NewConfig().WithGrouping(" a ", 4)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HighWaterMark is a generator's persisted progress through time.
+type HighWaterMark struct {
+	// Issued is a tick of an issued ID: the one issued when the mark was persisted.
+	Issued uint64
+	// Reserved is a tick which no issued ID's time component reaches until a new mark is persisted.
+	Reserved uint64
+}
+
+// TickStore persists a generator's high-water mark. Implementations must be durable across process restarts.
+type TickStore interface {
+	// Load returns the persisted mark, or ok=false if none has been persisted yet.
+	Load() (mark HighWaterMark, ok bool, err error)
+	// Store persists the mark.
+	Store(mark HighWaterMark) error
+}
+
+// BehindPolicy determines what Generate does while the clock is behind the persisted high-water mark.
+type BehindPolicy int
+
+const (
+	// BehindWait blocks until the clock passes the mark's reserved tick, so no ID is older than any issued
+	// before a restart, at the cost of waiting up to the persist interval after one.
+	BehindWait BehindPolicy = iota
+	// BehindError returns ErrClockBehind while the clock is behind the mark's issued tick. A clean restart
+	// doesn't wait, but IDs may be older than those issued in the persist interval before it.
+	BehindError
+)
+
+// ErrClockBehind is returned by Generate when the clock is behind the persisted high-water mark and the
+// policy is BehindError.
+var ErrClockBehind = errors.New("current time is behind the persisted high-water mark")
+
+// WithHighWaterMark persists a high-water mark to the store, so that a restarted generator won't issue IDs
+// from before those it issued previously, even if the host clock is now behind. Rather than writing on every
+// ID, each persisted mark records the current tick as issued and reserves persistEvery ahead of it, and is
+// renewed once the clock passes the reservation. On startup, Generate follows the policy while the clock is
+// behind the loaded mark. The config must have a time component.
+func (c Config) WithHighWaterMark(store TickStore, persistEvery time.Duration, policy BehindPolicy) Config {
+	c.tickStore = store
+	c.persistEvery = persistEvery
+	c.behindPolicy = policy
+	return c
+}
+
+// validateHighWaterMark ensures the high-water mark config is usable.
+func validateHighWaterMark(config Config) error {
+	if config.tickStore == nil {
+		return nil
+	}
+	if config.tickSize <= 0 {
+		return errors.New("a high-water mark requires a positive tick size")
+	}
+	if config.persistEvery < config.tickSize {
+		return errors.New("high-water mark persist interval cannot be shorter than the tick size")
+	}
+	if config.behindPolicy != BehindWait && config.behindPolicy != BehindError {
+		return errors.New("unknown behind policy")
+	}
+	return nil
+}
+
+// loadHighWaterMark loads the persisted high-water mark, setting the tick the clock must reach before IDs
+// are issued according to the behind policy.
+func (g *Generator) loadHighWaterMark() error {
+	if g.config.tickStore == nil {
+		return nil
+	}
+
+	mark, ok, err := g.config.tickStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load high-water mark: %w", err)
+	}
+	if mark.Issued > mark.Reserved {
+		return fmt.Errorf("invalid high-water mark: issued tick %d is past reserved tick %d", mark.Issued, mark.Reserved)
+	}
+	if ok {
+		g.floor = mark.Issued
+		if g.config.behindPolicy == BehindWait {
+			g.floor = mark.Reserved + 1
+		}
+		g.reserved = mark.Reserved
+	}
+	return nil
+}
+
+// checkHighWaterMark ensures the ticks have reached the floor set at startup, following the behind policy
+// if not, and persists a new mark once the ticks reach the reserved one.
+func (g *Generator) checkHighWaterMark(ticks uint64) (uint64, error) {
+	if g.config.tickStore == nil {
+		return ticks, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for ticks < g.floor {
+		if g.config.behindPolicy == BehindError {
+			return 0, ErrClockBehind
+		}
+		floorTime := g.config.epoch.Add(time.Duration(g.floor) * g.config.tickSize)
+		time.Sleep(floorTime.Sub(g.config.timeProvider()))
+		var err error
+		ticks, err = g.currentTicks()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if ticks >= g.reserved {
+		mark := HighWaterMark{Issued: ticks, Reserved: ticks + uint64(g.config.persistEvery/g.config.tickSize)}
+		err := g.config.tickStore.Store(mark)
+		if err != nil {
+			return 0, fmt.Errorf("failed to persist high-water mark: %w", err)
+		}
+		g.reserved = mark.Reserved
+	}
+	return ticks, nil
+}
+
+// FileTickStore is a TickStore backed by a file, which is replaced atomically on each store.
+type FileTickStore struct {
+	path string
+}
+
+// NewFileTickStore returns a TickStore persisting to the file at the given path.
+func NewFileTickStore(path string) *FileTickStore {
+	return &FileTickStore{path: path}
+}
+
+// Load reads the persisted mark, returning ok=false if the file doesn't exist.
+func (s *FileTickStore) Load() (HighWaterMark, bool, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return HighWaterMark{}, false, nil
+	}
+	if err != nil {
+		return HighWaterMark{}, false, err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return HighWaterMark{}, false, fmt.Errorf("invalid high-water mark in %s: expected issued and reserved ticks", s.path)
+	}
+	var ticks [2]uint64
+	for i, field := range fields {
+		ticks[i], err = strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return HighWaterMark{}, false, fmt.Errorf("invalid tick in %s: %w", s.path, err)
+		}
+	}
+	return HighWaterMark{Issued: ticks[0], Reserved: ticks[1]}, true, nil
+}
+
+// Store durably writes the mark's issued and reserved ticks, via a temporary file which replaces the
+// previous one.
+func (s *FileTickStore) Store(mark HighWaterMark) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(strconv.FormatUint(mark.Issued, 10) + " " + strconv.FormatUint(mark.Reserved, 10) + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
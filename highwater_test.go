@@ -0,0 +1,171 @@
+package flexid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// memoryTickStore is a TickStore which records every stored mark.
+type memoryTickStore struct {
+	stored  []HighWaterMark
+	loadErr error
+	err     error
+}
+
+func (s *memoryTickStore) Load() (HighWaterMark, bool, error) {
+	if len(s.stored) == 0 {
+		return HighWaterMark{}, false, s.loadErr
+	}
+	return s.stored[len(s.stored)-1], true, s.loadErr
+}
+
+func (s *memoryTickStore) Store(mark HighWaterMark) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.stored = append(s.stored, mark)
+	return nil
+}
+
+func Test_FileTickStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "highwater")
+	store := NewFileTickStore(path)
+
+	if _, ok, err := store.Load(); ok || err != nil {
+		t.Errorf("Expected no tick before storing, got ok=%v, err=%v", ok, err)
+	}
+
+	for _, mark := range []HighWaterMark{{Issued: 32, Reserved: 42}, {Issued: 1<<64 - 2, Reserved: 1<<64 - 1}} {
+		if err := store.Store(mark); err != nil {
+			t.Fatalf("Store(%+v) failed: %v", mark, err)
+		}
+		loaded, ok, err := store.Load()
+		if err != nil || !ok || loaded != mark {
+			t.Errorf("Load() got %+v, %v, %v, want %+v", loaded, ok, err, mark)
+		}
+	}
+
+	for _, content := range []string{"garbage", "42", "1 2 3", "1 x"} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := store.Load(); err == nil {
+			t.Errorf("Expected an error loading %q, but got nil", content)
+		}
+	}
+}
+
+func Test_HighWaterMark_Persists(t *testing.T) {
+	offset := 100 * time.Second
+	store := &memoryTickStore{}
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithHighWaterMark(store, 10*time.Second, BehindError), &offset))
+
+	for _, seconds := range []time.Duration{100, 105, 109, 110, 125} {
+		offset = seconds * time.Second
+		gen.MustGenerate()
+	}
+
+	expected := []HighWaterMark{{Issued: 100, Reserved: 110}, {Issued: 110, Reserved: 120}, {Issued: 125, Reserved: 135}}
+	if !slices.Equal(store.stored, expected) {
+		t.Errorf("Expected stored marks %v, got %v", expected, store.stored)
+	}
+}
+
+func Test_HighWaterMark_ClockBehindAfterRestart(t *testing.T) {
+	offset := 50 * time.Second
+	store := &memoryTickStore{stored: []HighWaterMark{{Issued: 100, Reserved: 110}}}
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithHighWaterMark(store, 10*time.Second, BehindError), &offset))
+
+	for _, seconds := range []time.Duration{50, 99} {
+		offset = seconds * time.Second
+		if _, err := gen.Generate(); !errors.Is(err, ErrClockBehind) {
+			t.Errorf("Expected ErrClockBehind at %ds, got %v", seconds, err)
+		}
+	}
+
+	offset = 105 * time.Second
+	if parsed := gen.MustParse(gen.MustGenerate()); parsed.Ticks != 105 {
+		t.Errorf("Expected tick 105 once the clock reaches the issued tick, got %d", parsed.Ticks)
+	}
+	if len(store.stored) != 1 {
+		t.Errorf("Expected the mark to be kept until the clock reaches its reservation, got %v", store.stored)
+	}
+}
+
+func Test_HighWaterMark_CleanRestart(t *testing.T) {
+	offset := 100 * time.Second
+	store := NewFileTickStore(filepath.Join(t.TempDir(), "highwater"))
+	config := withFakeClock(NewConfig().WithHighWaterMark(store, 10*time.Second, BehindError), &offset)
+
+	first := MustNewGenerator(config).MustGenerate()
+	offset += 50 * time.Millisecond
+	second, err := MustNewGenerator(config).Generate()
+	if err != nil {
+		t.Fatalf("Expected a restart with a correct clock to generate, got %v", err)
+	}
+	if second <= first {
+		t.Errorf("Expected %q to sort after %q", second, first)
+	}
+}
+
+func Test_HighWaterMark_Wait(t *testing.T) {
+	epoch := time.Now().Add(-time.Second)
+	gen := MustNewGenerator(NewConfig().WithEpoch(epoch).WithTickSize(Millisecond))
+	current := gen.MustParse(gen.MustGenerate()).Ticks
+
+	store := &memoryTickStore{stored: []HighWaterMark{{Issued: current + 20, Reserved: current + 30}}}
+	gen = MustNewGenerator(NewConfig().
+		WithEpoch(epoch).
+		WithTickSize(Millisecond).
+		WithHighWaterMark(store, time.Second, BehindWait))
+
+	parsed := gen.MustParse(gen.MustGenerate())
+	if parsed.Ticks <= current+30 {
+		t.Errorf("Expected generation to wait until after tick %d, got tick %d", current+30, parsed.Ticks)
+	}
+}
+
+func Test_HighWaterMark_StoreErrors(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithHighWaterMark(&memoryTickStore{err: errors.New("disk full")}, time.Second, BehindWait))
+	if _, err := gen.Generate(); err == nil {
+		t.Error("Expected an error when the high-water mark can't be persisted, but got nil")
+	}
+
+	_, err := NewGenerator(NewConfig().WithHighWaterMark(&memoryTickStore{loadErr: errors.New("corrupt")}, time.Second, BehindWait))
+	if err == nil {
+		t.Error("Expected an error when the high-water mark can't be loaded, but got nil")
+	}
+
+	store := &memoryTickStore{stored: []HighWaterMark{{Issued: 110, Reserved: 100}}}
+	if _, err := NewGenerator(NewConfig().WithHighWaterMark(store, time.Second, BehindWait)); err == nil {
+		t.Error("Expected an error when the issued tick is past the reserved one, but got nil")
+	}
+}
+
+func Test_HighWaterMark_Validation(t *testing.T) {
+	store := &memoryTickStore{}
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"No Tick Size", NewConfig().WithTickSize(0).WithHighWaterMark(store, time.Second, BehindWait)},
+		{"Interval Shorter Than Tick", NewConfig().WithTickSize(Second).WithHighWaterMark(store, time.Millisecond, BehindWait)},
+		{"Unknown Policy", NewConfig().WithHighWaterMark(store, time.Second, BehindPolicy(42))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
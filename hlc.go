@@ -0,0 +1,59 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithHybridClock makes the generator a hybrid logical clock (HLC), for IDs which respect causality across
+// nodes despite clock skew. The time component becomes max(physical, last, observed), followed by a logical
+// counter of counterWidth characters, which increments while the time component stays the same. Use
+// Observe to advance the clock past IDs received from other nodes. Observed IDs more than maxDrift ahead
+// of the local clock are rejected, guarding against a node with a wildly wrong clock; 0 disables the check.
+//
+// This is equivalent to WithSequence(counterWidth, OverflowBorrow), with a limit on Observe, except that the
+// counter must come before any node ID so that IDs from different nodes are ordered by the clock. The
+// default layout is then the time component, counter, node, and random part.
+func (c Config) WithHybridClock(counterWidth int, maxDrift time.Duration) Config {
+	c.sequenceWidth = counterWidth
+	c.overflowPolicy = OverflowBorrow
+	c.maxDrift = maxDrift
+	c.hybridClock = true
+	return c
+}
+
+// Observe advances the generator's clock past the given ID, which must have been generated with the same
+// configuration, typically by another node. With WithHybridClock, IDs generated afterwards are guaranteed
+// to be greater than it. With WithSequence, that only holds if the layout has no node segment before the
+// sequence.
+func (g *Generator) Observe(id string) error {
+	if g.config.sequenceWidth == 0 {
+		return errors.New("observing IDs requires a sequence or hybrid clock")
+	}
+
+	parsed, err := g.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	if g.config.maxDrift > 0 {
+		ticks, err := g.currentTicks()
+		if err != nil {
+			return err
+		}
+		maxTicks := ticks + uint64(g.config.maxDrift/g.config.tickSize)
+		if parsed.Ticks > maxTicks {
+			return fmt.Errorf("observed ID %q is more than %v ahead of the local clock", id, g.config.maxDrift)
+		}
+	}
+
+	return g.updateSequence(func() error {
+		if !g.hasSequence || parsed.Ticks > g.lastTick || (parsed.Ticks == g.lastTick && parsed.Sequence > g.sequence) {
+			g.hasSequence = true
+			g.lastTick = parsed.Ticks
+			g.sequence = parsed.Sequence
+		}
+		return nil
+	})
+}
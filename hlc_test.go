@@ -0,0 +1,140 @@
+package flexid
+
+import (
+	"testing"
+	"time"
+)
+
+// hlcTestGenerator returns a hybrid clock generator with fixed-width, sortable IDs, whose clock is
+// controlled by the returned pointer.
+func hlcTestGenerator(maxDrift time.Duration) (*Generator, *time.Duration) {
+	config, offset := fakeClockTestConfig(DefaultAlphabet, 0)
+	gen := MustNewGenerator(config.
+		WithHybridClock(2, maxDrift).
+		WithLayout(MustParseLayout("{time:4}{seq}{rand:3}")))
+	return gen, offset
+}
+
+func Test_HybridClock_CausalityAcrossSkewedNodes(t *testing.T) {
+	ahead, aheadOffset := hlcTestGenerator(0)
+	behind, behindOffset := hlcTestGenerator(0)
+	*aheadOffset = 10 * time.Second
+	*behindOffset = 5 * time.Second
+
+	sent := ahead.MustGenerate()
+	if err := behind.Observe(sent); err != nil {
+		t.Fatalf("Observe(%q) failed: %v", sent, err)
+	}
+	reply := behind.MustGenerate()
+
+	if reply <= sent {
+		t.Errorf("Expected reply %q to sort after the observed ID %q", reply, sent)
+	}
+	parsed := behind.MustParse(reply)
+	if parsed.Ticks != 10 || parsed.Sequence != 1 {
+		t.Errorf("Expected tick 10 and counter 1, got %+v", parsed)
+	}
+
+	// Once the local clock catches up, the counter resets.
+	*behindOffset = 11 * time.Second
+	if parsed := behind.MustParse(behind.MustGenerate()); parsed.Ticks != 11 || parsed.Sequence != 0 {
+		t.Errorf("Expected tick 11 and counter 0, got %+v", parsed)
+	}
+}
+
+func Test_HybridClock_CausalityAcrossNodeIDs(t *testing.T) {
+	var aheadOffset, behindOffset time.Duration = 10 * time.Second, 5 * time.Second
+	ahead := MustNewGenerator(withFakeClock(NewConfig().WithHybridClock(2, 0).WithNodeID(5, 1), &aheadOffset))
+	behind := MustNewGenerator(withFakeClock(NewConfig().WithHybridClock(2, 0).WithNodeID(2, 1), &behindOffset))
+
+	sent := ahead.MustGenerate()
+	if err := behind.Observe(sent); err != nil {
+		t.Fatalf("Observe(%q) failed: %v", sent, err)
+	}
+	if reply := behind.MustGenerate(); reply <= sent {
+		t.Errorf("Expected reply %q from a lower node ID to sort after the observed ID %q", reply, sent)
+	}
+}
+
+func Test_HybridClock_ObserveOlderID(t *testing.T) {
+	gen, offset := hlcTestGenerator(0)
+	other, otherOffset := hlcTestGenerator(0)
+	*offset = 10 * time.Second
+	*otherOffset = 5 * time.Second
+
+	gen.MustGenerate()
+	if err := gen.Observe(other.MustGenerate()); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if parsed := gen.MustParse(gen.MustGenerate()); parsed.Ticks != 10 || parsed.Sequence != 1 {
+		t.Errorf("Expected observing an older ID to have no effect, got %+v", parsed)
+	}
+}
+
+func Test_HybridClock_ObserveSameTick(t *testing.T) {
+	gen, offset := hlcTestGenerator(0)
+	other, otherOffset := hlcTestGenerator(0)
+	*offset = 10 * time.Second
+	*otherOffset = 10 * time.Second
+
+	var observed string
+	for i := 0; i < 5; i++ {
+		observed = other.MustGenerate()
+	}
+	if err := gen.Observe(observed); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if parsed := gen.MustParse(gen.MustGenerate()); parsed.Ticks != 10 || parsed.Sequence != 5 {
+		t.Errorf("Expected counter to continue past the observed ID, got %+v", parsed)
+	}
+}
+
+func Test_HybridClock_CounterOverflowBorrows(t *testing.T) {
+	gen, offset := hlcTestGenerator(0)
+	*offset = 10 * time.Second
+
+	// The maximum 2 character counter in Base62.
+	if err := gen.Observe("000Azzzzz"); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if parsed := gen.MustParse(gen.MustGenerate()); parsed.Ticks != 11 || parsed.Sequence != 0 {
+		t.Errorf("Expected counter overflow to advance the time component, got %+v", parsed)
+	}
+}
+
+func Test_HybridClock_MaxDrift(t *testing.T) {
+	gen, offset := hlcTestGenerator(5 * time.Second)
+	other, otherOffset := hlcTestGenerator(0)
+	*offset = 10 * time.Second
+
+	*otherOffset = 15 * time.Second
+	if err := gen.Observe(other.MustGenerate()); err != nil {
+		t.Errorf("Expected ID within max drift to be observed, got: %v", err)
+	}
+
+	*otherOffset = 16 * time.Second
+	if err := gen.Observe(other.MustGenerate()); err == nil {
+		t.Error("Expected an error observing an ID beyond the max drift, but got nil")
+	}
+}
+
+func Test_HybridClock_ObserveErrors(t *testing.T) {
+	gen, _ := hlcTestGenerator(0)
+	if err := gen.Observe("not an id"); err == nil {
+		t.Error("Expected an error observing an invalid ID, but got nil")
+	}
+
+	plain := MustNewGenerator(NewConfig())
+	if err := plain.Observe(plain.MustGenerate()); err == nil {
+		t.Error("Expected an error observing without a sequence, but got nil")
+	}
+
+	if _, err := NewGenerator(NewConfig().WithHybridClock(2, -time.Second)); err == nil {
+		t.Error("Expected an error for a negative max drift, but got nil")
+	}
+
+	config := NewConfig().WithHybridClock(2, 0).WithNodeID(5, 1).WithLayout(MustParseLayout("{time}{node}{seq}{rand}"))
+	if _, err := NewGenerator(config); err == nil {
+		t.Error("Expected an error for a node segment before the counter, but got nil")
+	}
+}
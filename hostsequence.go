@@ -0,0 +1,125 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// hostSequenceRecordLen is the length of a host sequence file's record: two fixed-width decimal numbers,
+// so each update overwrites the previous one in place.
+const hostSequenceRecordLen = 41
+
+// HostSequence is a sequence counter shared by the processes on a host, via an exclusively locked file.
+// Generators in different processes using the same HostSequence file never issue the same tick and
+// sequence number, so their IDs can't collide however few random characters they have. Generators in the
+// same process may share a HostSequence.
+//
+// Alternatively, a NodeLease gives each process a distinct node ID, avoiding the per-ID file lock.
+type HostSequence struct {
+	mu   sync.Mutex // Excludes generators sharing the file in this process, which its lock doesn't.
+	file *os.File
+}
+
+// OpenHostSequence opens the host sequence file at the given path, creating it if needed. Every process
+// sharing it must use the same configuration. It must be closed with Close once no longer used.
+func OpenHostSequence(path string) (*HostSequence, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open host sequence: %w", err)
+	}
+	return &HostSequence{file: file}, nil
+}
+
+// Close closes the host sequence file.
+func (s *HostSequence) Close() error {
+	return s.file.Close()
+}
+
+// WithHostSequence adds a sequence segment as with WithSequence, but shares the sequence with other
+// processes on the host via the given HostSequence. Each ID then takes a file lock, so generation is
+// slower than with an in-process sequence.
+func (c Config) WithHostSequence(hostSequence *HostSequence, width int, policy OverflowPolicy) Config {
+	c = c.WithSequence(width, policy)
+	c.hostSequence = hostSequence
+	return c
+}
+
+// updateSequence calls update with the sequence state locked, loading it from and saving it to the host
+// sequence if there is one.
+func (g *Generator) updateSequence(update func() error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	hostSequence := g.config.hostSequence
+	if hostSequence == nil {
+		return update()
+	}
+
+	hostSequence.mu.Lock()
+	defer hostSequence.mu.Unlock()
+
+	err := lockOpenFile(hostSequence.file)
+	if err != nil {
+		return fmt.Errorf("failed to lock host sequence: %w", err)
+	}
+
+	err = hostSequence.load(g)
+	if err == nil {
+		err = update()
+	}
+	if err == nil && g.hasSequence {
+		err = hostSequence.save(g)
+	}
+
+	unlockErr := unlockOpenFile(hostSequence.file)
+	if err == nil && unlockErr != nil {
+		err = fmt.Errorf("failed to unlock host sequence: %w", unlockErr)
+	}
+	return err
+}
+
+// load reads the shared sequence state into the generator. An empty file means no sequence number has
+// been issued yet.
+func (s *HostSequence) load(g *Generator) error {
+	record := make([]byte, hostSequenceRecordLen)
+	n, err := s.file.ReadAt(record, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read host sequence: %w", err)
+	}
+	if n == 0 {
+		g.hasSequence = false
+		return nil
+	}
+
+	fields := strings.Fields(string(record[:n]))
+	if len(fields) != 2 {
+		return fmt.Errorf("invalid host sequence %q", record[:n])
+	}
+	lastTick, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid host sequence tick: %w", err)
+	}
+	sequence, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid host sequence number: %w", err)
+	}
+	g.hasSequence = true
+	g.lastTick = lastTick
+	g.sequence = sequence
+	return nil
+}
+
+// save writes the generator's sequence state to the shared file.
+func (s *HostSequence) save(g *Generator) error {
+	record := fmt.Sprintf("%020d %020d", g.lastTick, g.sequence)
+	_, err := s.file.WriteAt([]byte(record), 0)
+	if err != nil {
+		return fmt.Errorf("failed to write host sequence: %w", err)
+	}
+	return nil
+}
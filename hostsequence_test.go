@@ -0,0 +1,213 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package flexid
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openTestHostSequence opens the host sequence at the given path, closing it when the test ends.
+func openTestHostSequence(t *testing.T, path string) *HostSequence {
+	t.Helper()
+	hostSequence, err := OpenHostSequence(path)
+	if err != nil {
+		t.Fatalf("OpenHostSequence failed: %v", err)
+	}
+	t.Cleanup(func() { hostSequence.Close() })
+	return hostSequence
+}
+
+func Test_HostSequence_SharedBetweenGenerators(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	first := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowError))
+	second := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowError))
+
+	ids := []string{first.MustGenerate(), second.MustGenerate(), first.MustGenerate()}
+	*offset = 6 * time.Second
+	ids = append(ids, second.MustGenerate(), first.MustGenerate())
+
+	expected := []string{"50", "51", "52", "60", "61"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected IDs %v, got %v", expected, ids)
+	}
+}
+
+func Test_HostSequence_PersistsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	if id := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowError)).MustGenerate(); id != "50" {
+		t.Fatalf("Expected 50, got %q", id)
+	}
+
+	// A restarted process carries on from the shared state, even with its clock behind.
+	*offset = 4 * time.Second
+	if id := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowError)).MustGenerate(); id != "51" {
+		t.Errorf("Expected 51, got %q", id)
+	}
+}
+
+func Test_HostSequence_BlocksAndObserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	first := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowBorrow))
+	second := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowBorrow))
+
+	block, err := first.ReserveBlock(3)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+	if block.First != "50" || block.Last != "52" {
+		t.Errorf("Expected block 50-52, got %+v", block)
+	}
+	if id := second.MustGenerate(); id != "53" {
+		t.Errorf("Expected 53 after the other generator's block, got %q", id)
+	}
+
+	if err := second.Observe("75"); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if id := first.MustGenerate(); id != "76" {
+		t.Errorf("Expected 76 after the other generator observed 75, got %q", id)
+	}
+}
+
+func Test_HostSequence_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	expectConcurrentUnique(t,
+		MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowBorrow)),
+		MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowBorrow)))
+}
+
+func Test_HostSequence_SharedHandle(t *testing.T) {
+	hostSequence := openTestHostSequence(t, filepath.Join(t.TempDir(), "sequence"))
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	expectConcurrentUnique(t,
+		MustNewGenerator(config.WithHostSequence(hostSequence, 1, OverflowBorrow)),
+		MustNewGenerator(config.WithHostSequence(hostSequence, 1, OverflowBorrow)))
+}
+
+// expectConcurrentUnique generates IDs from the generators concurrently, expecting no duplicates.
+func expectConcurrentUnique(t *testing.T, generators ...*Generator) {
+	t.Helper()
+	const perGoroutine = 500
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4*len(generators); i++ {
+		wg.Add(1)
+		go func(gen *Generator) {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				id := gen.MustGenerate()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Duplicate ID %q", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}(generators[i%len(generators)])
+	}
+	wg.Wait()
+}
+
+func Test_HostSequence_Validation(t *testing.T) {
+	hostSequence, err := OpenHostSequence(filepath.Join(t.TempDir(), "sequence"))
+	if err != nil {
+		t.Fatalf("OpenHostSequence failed: %v", err)
+	}
+	defer hostSequence.Close()
+
+	_, err = NewGenerator(NewConfig().WithHostSequence(hostSequence, 0, OverflowWait))
+	if err == nil {
+		t.Errorf("Expected an error for a host sequence without a sequence width")
+	}
+}
+
+func Test_HostSequence_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	*offset = 5 * time.Second
+	if _, err := MustNewGenerator(config.WithHostSequence(openTestHostSequence(t, path), 1, OverflowWait)).Generate(); err == nil {
+		t.Errorf("Expected an error for an invalid host sequence file")
+	}
+}
+
+func Test_HostSequence_MultipleProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequence")
+
+	const processes = 3
+	var outputs []*bufio.Scanner
+	for i := 0; i < processes; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^Test_HostSequence_HelperProcess$")
+		cmd.Env = append(os.Environ(), "FLEXID_HOST_SEQUENCE_HELPER_PATH="+path)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer cmd.Wait()
+		outputs = append(outputs, bufio.NewScanner(stdout))
+	}
+
+	seen := make(map[string]bool)
+	for _, output := range outputs {
+		for output.Scan() {
+			id := output.Text()
+			if id == "done" {
+				break
+			}
+			if seen[id] {
+				t.Fatalf("ID %q was generated by more than one process", id)
+			}
+			seen[id] = true
+		}
+	}
+	if len(seen) != processes*hostSequenceHelperIDs {
+		t.Errorf("Expected %d IDs, got %d", processes*hostSequenceHelperIDs, len(seen))
+	}
+}
+
+const hostSequenceHelperIDs = 500
+
+// Test_HostSequence_HelperProcess is run as a subprocess by Test_HostSequence_MultipleProcesses. It
+// prints IDs generated without any random characters using the shared host sequence, then "done".
+func Test_HostSequence_HelperProcess(t *testing.T) {
+	path := os.Getenv("FLEXID_HOST_SEQUENCE_HELPER_PATH")
+	if path == "" {
+		t.Skip("only run as a helper process")
+	}
+
+	hostSequence, err := OpenHostSequence(path)
+	if err != nil {
+		t.Fatalf("OpenHostSequence failed: %v", err)
+	}
+	defer hostSequence.Close()
+
+	gen := MustNewGenerator(NewConfig().WithNumRandomChars(0).WithHostSequence(hostSequence, 2, OverflowWait))
+	output := bufio.NewWriter(os.Stdout)
+	for i := 0; i < hostSequenceHelperIDs; i++ {
+		output.WriteString(gen.MustGenerate() + "\n")
+	}
+	output.WriteString("done\n")
+	output.Flush()
+}
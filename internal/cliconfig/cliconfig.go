@@ -0,0 +1,82 @@
+// Package cliconfig holds the command-line flags describing a generator's configuration, shared by the
+// flexid commands.
+package cliconfig
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amterp/flexid"
+)
+
+// namedAlphabets are the alphabets which may be given to -alphabet by name.
+var namedAlphabets = map[string]string{
+	"base62":    flexid.Base62Alphabet,
+	"base36":    flexid.Base36Alphabet,
+	"base16":    flexid.Base16LowerAlphabet,
+	"base64url": flexid.Base64UrlAlphabet,
+	"crockford": flexid.CrockfordBase32Alphabet,
+}
+
+// Flags are the flags describing a generator's configuration.
+type Flags struct {
+	Alphabet string
+	Tick     time.Duration
+	Epoch    string
+	Random   int
+}
+
+// AddFlags registers the config flags, with the library's defaults.
+func AddFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Alphabet, "alphabet", "base62",
+		"alphabet: base62, base36, base16, base64url, crockford, or the characters themselves")
+	fs.DurationVar(&f.Tick, "tick", flexid.Millisecond, "tick size, or 0 for no time component")
+	fs.StringVar(&f.Epoch, "epoch", "1970-01-01", "epoch, as a date or RFC 3339 timestamp")
+	fs.IntVar(&f.Random, "random", 5, "number of random characters")
+	return f
+}
+
+// Settings are the config flags, resolved.
+type Settings struct {
+	Alphabet string
+	Tick     time.Duration
+	Epoch    time.Time
+	Random   int
+}
+
+// Resolve resolves the flags into settings.
+func (f *Flags) Resolve() (Settings, error) {
+	alphabet, ok := namedAlphabets[strings.ToLower(f.Alphabet)]
+	if !ok {
+		alphabet = f.Alphabet
+	}
+
+	epoch, err := ParseTime(f.Epoch)
+	if err != nil {
+		return Settings{}, fmt.Errorf("invalid epoch: %w", err)
+	}
+	return Settings{Alphabet: alphabet, Tick: f.Tick, Epoch: epoch, Random: f.Random}, nil
+}
+
+// Config returns the generator config for the settings.
+func (s Settings) Config() flexid.Config {
+	return flexid.NewConfig().
+		WithAlphabet(s.Alphabet).
+		WithTickSize(s.Tick).
+		WithEpoch(s.Epoch).
+		WithNumRandomChars(s.Random)
+}
+
+// ParseTime parses a date or RFC 3339 timestamp, in UTC unless a zone is given.
+func ParseTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date or RFC 3339 timestamp", value)
+}
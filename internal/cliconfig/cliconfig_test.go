@@ -0,0 +1,74 @@
+package cliconfig
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/amterp/flexid"
+)
+
+func Test_Flags_Resolve(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	config := AddFlags(fs)
+	if err := fs.Parse([]string{"-alphabet", "Crockford", "-tick", "1s", "-epoch", "2025-01-01", "-random", "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := config.Resolve()
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	expected := Settings{
+		Alphabet: flexid.CrockfordBase32Alphabet,
+		Tick:     time.Second,
+		Epoch:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Random:   3,
+	}
+	if s != expected {
+		t.Errorf("Expected %+v, got %+v", expected, s)
+	}
+}
+
+func Test_Flags_LiteralAlphabet(t *testing.T) {
+	s, err := (&Flags{Alphabet: "xyz", Epoch: "1970-01-01"}).Resolve()
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if s.Alphabet != "xyz" {
+		t.Errorf("Expected the alphabet to be used as is, got %q", s.Alphabet)
+	}
+}
+
+func Test_ParseTime(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2025-01-02", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-02T03:04:05", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2025-01-02T03:04:05.5Z", time.Date(2025, 1, 2, 3, 4, 5, 500_000_000, time.UTC)},
+		{"2025-01-02T03:04:05+01:00", time.Date(2025, 1, 2, 2, 4, 5, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		parsed, err := ParseTime(tc.value)
+		if err != nil || !parsed.Equal(tc.expected) {
+			t.Errorf("ParseTime(%q) = %v, %v; expected %v", tc.value, parsed, err, tc.expected)
+		}
+	}
+
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Errorf("Expected an error for an invalid time")
+	}
+}
+
+func Test_Settings_Config(t *testing.T) {
+	s := Settings{Alphabet: "0123456789", Tick: time.Second, Epoch: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Random: 0}
+	gen, err := flexid.NewGenerator(s.Config().WithTimeProvider(func() time.Time { return s.Epoch.Add(42 * time.Second) }))
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+	if id := gen.MustGenerate(); id != "42" {
+		t.Errorf("Expected 42, got %q", id)
+	}
+}
@@ -0,0 +1,476 @@
+package flexid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// segmentKind identifies what a layout segment contains.
+type segmentKind int
+
+const (
+	segmentTime segmentKind = iota
+	segmentRandom
+	segmentLiteral
+	segmentNode
+	segmentSequence
+)
+
+// segmentNames maps the names used in layout templates to segment kinds.
+var segmentNames = map[string]segmentKind{
+	"time": segmentTime,
+	"rand": segmentRandom,
+	"node": segmentNode,
+	"seq":  segmentSequence,
+}
+
+// segment is a single component of a Layout.
+type segment struct {
+	kind    segmentKind
+	width   int    // Number of characters, or 0 for a variable-width or default-width segment.
+	literal string // Text of a literal segment.
+}
+
+// Layout describes how the segments of an ID are arranged, replacing the default of a variable-width
+// time component followed by the random part. Layouts can be built with NewLayout or parsed from a
+// template with ParseLayout, and are set with Config.WithLayout. Parse understands the same layout.
+type Layout struct {
+	segments []segment
+}
+
+// NewLayout returns an empty layout, to which segments can be appended.
+func NewLayout() Layout {
+	return Layout{}
+}
+
+// Time appends the time component. A width of 0 makes it variable-width; otherwise it's left-padded
+// with the alphabet's first character, keeping IDs sortable even as the time component grows.
+func (l Layout) Time(width int) Layout {
+	return l.with(segment{kind: segmentTime, width: width})
+}
+
+// Random appends a random segment. A width of 0 uses the config's number of random characters.
+func (l Layout) Random(width int) Layout {
+	return l.with(segment{kind: segmentRandom, width: width})
+}
+
+// Node appends the node ID. A width of 0 uses the width given to Config.WithNodeID.
+func (l Layout) Node(width int) Layout {
+	return l.with(segment{kind: segmentNode, width: width})
+}
+
+// Sequence appends the sequence number. A width of 0 uses the width given to Config.WithSequence.
+func (l Layout) Sequence(width int) Layout {
+	return l.with(segment{kind: segmentSequence, width: width})
+}
+
+// Literal appends fixed text, such as a prefix or separator.
+func (l Layout) Literal(text string) Layout {
+	return l.with(segment{kind: segmentLiteral, literal: text})
+}
+
+func (l Layout) with(s segment) Layout {
+	l.segments = append(slices.Clone(l.segments), s)
+	return l
+}
+
+// scaleRandomWidths returns the layout with the explicit widths of its random segments scaled to keep
+// their entropy when the alphabet shrinks, as for the config's number of random characters.
+func (l Layout) scaleRandomWidths(fromBase, toBase int) Layout {
+	l.segments = slices.Clone(l.segments)
+	for i, s := range l.segments {
+		if s.kind == segmentRandom {
+			l.segments[i].width = scaleRandomChars(s.width, fromBase, toBase)
+		}
+	}
+	return l
+}
+
+// ParseLayout parses a layout template such as "{time:8}-{rand:5}". Segments are written as {name} or
+// {name:width}, and any text outside braces is a literal. The available segments are:
+// - time: the time component, variable-width unless a width is given.
+// - rand: random characters, defaulting to the config's number of random characters.
+// - node: the node ID, defaulting to the width given to Config.WithNodeID.
+// - seq: the sequence number, defaulting to the width given to Config.WithSequence.
+func ParseLayout(template string) (Layout, error) {
+	layout := NewLayout()
+	for len(template) > 0 {
+		open := strings.IndexAny(template, "{}")
+		if open < 0 {
+			return layout.Literal(template), nil
+		}
+		if template[open] == '}' {
+			return Layout{}, fmt.Errorf("unexpected '}' in layout template at %q", template[open:])
+		}
+		if open > 0 {
+			layout = layout.Literal(template[:open])
+		}
+
+		end := strings.IndexByte(template[open:], '}')
+		if end < 0 {
+			return Layout{}, fmt.Errorf("unclosed '{' in layout template at %q", template[open:])
+		}
+		s, err := parseSegment(template[open+1 : open+end])
+		if err != nil {
+			return Layout{}, err
+		}
+		layout = layout.with(s)
+		template = template[open+end+1:]
+	}
+	return layout, nil
+}
+
+// MustParseLayout is like ParseLayout, but panics if the template is invalid.
+func MustParseLayout(template string) Layout {
+	layout, err := ParseLayout(template)
+	if err != nil {
+		panic("flexid: failed to parse layout: " + err.Error())
+	}
+	return layout
+}
+
+// parseSegment parses the inside of a {name} or {name:width} placeholder.
+func parseSegment(placeholder string) (segment, error) {
+	name, widthStr, hasWidth := strings.Cut(placeholder, ":")
+	kind, ok := segmentNames[name]
+	if !ok {
+		return segment{}, fmt.Errorf("unknown layout segment %q", name)
+	}
+
+	s := segment{kind: kind}
+	if hasWidth {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil || width <= 0 {
+			return segment{}, fmt.Errorf("invalid width %q for layout segment %q", widthStr, name)
+		}
+		s.width = width
+	}
+	return s, nil
+}
+
+// String returns the layout as a template understood by ParseLayout.
+func (l Layout) String() string {
+	var sb strings.Builder
+	for _, s := range l.segments {
+		if s.kind == segmentLiteral {
+			sb.WriteString(s.literal)
+			continue
+		}
+		for name, kind := range segmentNames {
+			if kind == s.kind {
+				sb.WriteString("{" + name)
+			}
+		}
+		if s.width > 0 {
+			sb.WriteString(":" + strconv.Itoa(s.width))
+		}
+		sb.WriteString("}")
+	}
+	return sb.String()
+}
+
+// WithLayout sets how the segments of generated IDs are arranged. See Layout.
+func (c Config) WithLayout(layout Layout) Config {
+	c.layout = layout
+	return c
+}
+
+// resolveLayout validates the config's layout and fills in default widths, returning nil if the
+// default layout is used. Configs with a node ID or sequence but no layout get a default layout of
+// the time component, node, sequence, and random part, or for hybrid clocks, the time component,
+// sequence, node, and random part.
+func resolveLayout(config Config) ([]segment, error) {
+	if config.nodeWidth < 0 || (config.nodeWidth == 0 && config.nodeID != 0) {
+		return nil, errors.New("node ID width must be positive")
+	}
+
+	layout := config.layout
+	if len(layout.segments) == 0 {
+		if config.nodeWidth == 0 && config.sequenceWidth == 0 {
+			return nil, nil
+		}
+		if config.tickSize > 0 {
+			layout = layout.Time(0)
+		}
+		if config.nodeWidth > 0 && !config.hybridClock {
+			layout = layout.Node(0)
+		}
+		if config.sequenceWidth > 0 {
+			layout = layout.Sequence(0)
+		}
+		if config.nodeWidth > 0 && config.hybridClock {
+			layout = layout.Node(0)
+		}
+		layout = layout.Random(0)
+	}
+
+	if config.words != nil {
+		return nil, errors.New("a layout, node ID, or sequence cannot be combined with a word list")
+	}
+
+	var segments []segment
+	timeSegments, nodeSegments, sequenceSegments, variableSegments, valueSegments := 0, 0, 0, 0, 0
+	for _, s := range layout.segments {
+		if s.width < 0 {
+			return nil, fmt.Errorf("layout segment widths cannot be negative, got %d", s.width)
+		}
+
+		switch s.kind {
+		case segmentTime:
+			if config.tickSize <= 0 {
+				return nil, errors.New("a layout with a time segment requires a positive tick size")
+			}
+			timeSegments++
+			if s.width == 0 {
+				variableSegments++
+			}
+		case segmentRandom:
+			if s.width == 0 {
+				s.width = config.numRandomChars
+			}
+			if s.width == 0 {
+				continue
+			}
+		case segmentNode:
+			if config.nodeWidth == 0 {
+				return nil, errors.New("layout contains a node segment, but no node ID is configured")
+			}
+			if config.hybridClock && sequenceSegments == 0 {
+				return nil, errors.New("a hybrid clock's layout must put the sequence before the node segment")
+			}
+			if s.width == 0 {
+				s.width = config.nodeWidth
+			}
+			err := validateNodeID(config.nodeID, s.width, len(config.alphabet))
+			if err != nil {
+				return nil, err
+			}
+			nodeSegments++
+		case segmentSequence:
+			if config.sequenceWidth == 0 {
+				return nil, errors.New("layout contains a sequence segment, but no sequence is configured")
+			}
+			if s.width == 0 {
+				s.width = config.sequenceWidth
+			}
+			sequenceSegments++
+		case segmentLiteral:
+			if s.literal == "" {
+				continue
+			}
+			if config.separator != "" && strings.Contains(s.literal, config.separator) {
+				return nil, fmt.Errorf("layout literal %q contains the grouping separator %q", s.literal, config.separator)
+			}
+		}
+
+		if s.kind != segmentLiteral {
+			valueSegments++
+		}
+		segments = append(segments, s)
+	}
+
+	if valueSegments == 0 {
+		return nil, errors.New("layout must contain at least one non-literal segment")
+	}
+	if timeSegments > 1 {
+		return nil, errors.New("layout cannot contain more than one time segment")
+	}
+	if nodeSegments > 1 {
+		return nil, errors.New("layout cannot contain more than one node segment")
+	}
+	if config.nodeWidth > 0 && nodeSegments == 0 {
+		return nil, errors.New("a node ID is configured, but the layout has no node segment")
+	}
+	if sequenceSegments > 1 {
+		return nil, errors.New("layout cannot contain more than one sequence segment")
+	}
+	if config.sequenceWidth > 0 && sequenceSegments == 0 {
+		return nil, errors.New("a sequence is configured, but the layout has no sequence segment")
+	}
+	if variableSegments > 1 {
+		return nil, errors.New("layout cannot contain more than one variable-width segment")
+	}
+	return segments, nil
+}
+
+// generateLayout generates an ID arranged according to the generator's layout.
+func (g *Generator) generateLayout(ctx context.Context, ticks uint64) (string, error) {
+	var sequence uint64
+	for _, s := range g.segments {
+		if s.kind == segmentSequence {
+			var err error
+			ticks, sequence, err = g.nextSequence(ticks, s.width)
+			if err != nil {
+				return "", err
+			}
+			// Borrowing may have moved past the persisted high-water mark.
+			ticks, err = g.checkHighWaterMark(ticks)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return g.generateSegments(ctx, ticks, sequence)
+}
+
+// generateSegments generates an ID from the layout's segments, using the given tick and sequence number.
+func (g *Generator) generateSegments(ctx context.Context, ticks, sequence uint64) (string, error) {
+	parts := make([]string, len(g.segments))
+	for i, s := range g.segments {
+		switch s.kind {
+		case segmentTime:
+			encoded, err := g.encodeFixedWidth(ticks, s.width)
+			if err != nil {
+				return "", fmt.Errorf("time component: %w", err)
+			}
+			parts[i] = encoded
+		case segmentNode:
+			encoded, err := g.encodeFixedWidth(g.config.nodeID, s.width)
+			if err != nil {
+				return "", fmt.Errorf("node ID: %w", err)
+			}
+			parts[i] = encoded
+		case segmentSequence:
+			encoded, err := g.encodeFixedWidth(sequence, s.width)
+			if err != nil {
+				return "", fmt.Errorf("sequence: %w", err)
+			}
+			parts[i] = encoded
+		case segmentLiteral:
+			parts[i] = s.literal
+		}
+	}
+
+	// Random segments are regenerated while the ID contains a blocked word or isn't unique.
+	for attempt := 1; ; attempt++ {
+		var randomRanges [][2]int
+		length := 0
+		for i, s := range g.segments {
+			if s.kind == segmentRandom {
+				chars, err := g.generateRandomPart(s.width)
+				if err != nil {
+					return "", err
+				}
+				parts[i] = chars
+				randomRanges = append(randomRanges, [2]int{length, length + len(chars)})
+			}
+			length += len(parts[i])
+		}
+
+		id := strings.Join(parts, "")
+		reason := rejectBlocked
+		if !g.containsBlockedWord(id, randomRanges...) {
+			var err error
+			reason, err = g.claimUnique(ctx, ticks, id)
+			if err != nil {
+				return "", err
+			}
+		}
+		if reason == accepted {
+			g.generated.Add(1)
+			return g.group(id), nil
+		}
+		err := g.rejectRandom(attempt, reason, len(randomRanges) > 0)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// encodeFixedWidth encodes the number in the alphabet, left-padded to the given width. A width of 0
+// leaves the encoding unpadded.
+func (g *Generator) encodeFixedWidth(number uint64, width int) (string, error) {
+	encoded, err := g.encodeBaseN(number)
+	if err != nil {
+		return "", err
+	}
+	if width == 0 {
+		return encoded, nil
+	}
+	if len(encoded) > width {
+		return "", fmt.Errorf("%d does not fit in %d characters", number, width)
+	}
+	return strings.Repeat(g.config.alphabet[:1], width-len(encoded)) + encoded, nil
+}
+
+// parseLayout is Parse for generators using a layout.
+func (g *Generator) parseLayout(id string) (ParsedID, error) {
+	id = g.ungroup(id)
+
+	fixedLen, hasVariable := 0, false
+	for _, s := range g.segments {
+		switch {
+		case s.kind == segmentLiteral:
+			fixedLen += len(s.literal)
+		case s.width == 0:
+			hasVariable = true
+		default:
+			fixedLen += s.width
+		}
+	}
+
+	variableLen := len(id) - fixedLen
+	if variableLen < 0 || (hasVariable && variableLen == 0) {
+		return ParsedID{}, fmt.Errorf("ID %q is too short", id)
+	}
+	if !hasVariable && variableLen > 0 {
+		return ParsedID{}, fmt.Errorf("ID %q is too long", id)
+	}
+
+	var parsed ParsedID
+	var random strings.Builder
+	pos := 0
+	for _, s := range g.segments {
+		width := s.width
+		switch {
+		case s.kind == segmentLiteral:
+			width = len(s.literal)
+		case width == 0:
+			width = variableLen
+		}
+		part := id[pos : pos+width]
+		pos += width
+
+		switch s.kind {
+		case segmentTime:
+			ticks, err := g.decodeBaseN(part)
+			if err != nil {
+				return ParsedID{}, fmt.Errorf("invalid time component in ID %q: %w", id, err)
+			}
+			parsed.Ticks = ticks
+			parsed.Time, err = g.tickTime(ticks)
+			if err != nil {
+				return ParsedID{}, err
+			}
+		case segmentNode:
+			node, err := g.decodeBaseN(part)
+			if err != nil {
+				return ParsedID{}, fmt.Errorf("invalid node ID in ID %q: %w", id, err)
+			}
+			parsed.Node = node
+		case segmentSequence:
+			sequence, err := g.decodeBaseN(part)
+			if err != nil {
+				return ParsedID{}, fmt.Errorf("invalid sequence in ID %q: %w", id, err)
+			}
+			parsed.Sequence = sequence
+		case segmentRandom:
+			err := g.validateRandomPart(part)
+			if err != nil {
+				return ParsedID{}, fmt.Errorf("invalid random part in ID %q: %w", id, err)
+			}
+			random.WriteString(part)
+		case segmentLiteral:
+			if part != s.literal {
+				return ParsedID{}, fmt.Errorf("ID %q does not contain the literal %q at position %d", id, s.literal, pos-width)
+			}
+		}
+	}
+	parsed.Random = random.String()
+
+	return parsed, nil
+}
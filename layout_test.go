@@ -0,0 +1,172 @@
+package flexid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_ParseLayout(t *testing.T) {
+	testCases := []struct {
+		template string
+		expected Layout
+	}{
+		{"{time}{rand}", NewLayout().Time(0).Random(0)},
+		{"{time:8}-{rand:5}", NewLayout().Time(8).Literal("-").Random(5)},
+		{"ord_{rand:3}{time}", NewLayout().Literal("ord_").Random(3).Time(0)},
+		{"{rand:10}", NewLayout().Random(10)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			layout, err := ParseLayout(tc.template)
+			if err != nil {
+				t.Fatalf("ParseLayout failed: %v", err)
+			}
+			if layout.String() != tc.template {
+				t.Errorf("String() got %q, want %q", layout.String(), tc.template)
+			}
+			if tc.expected.String() != tc.template {
+				t.Errorf("Builder String() got %q, want %q", tc.expected.String(), tc.template)
+			}
+		})
+	}
+}
+
+func Test_ParseLayout_Invalid(t *testing.T) {
+	for _, template := range []string{"{time", "time}", "{foo}", "{rand:0}", "{rand:x}", "{time:-1}"} {
+		if _, err := ParseLayout(template); err == nil {
+			t.Errorf("Expected an error parsing %q, but got nil", template)
+		}
+	}
+}
+
+func Test_Layout_GenerateAndParse(t *testing.T) {
+	offset := 62 * time.Second
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithLayout(MustParseLayout("ord_{time:6}-{rand:3}")).
+		WithRandomSource(&sameByteReader{b: 123}), &offset))
+
+	id := gen.MustGenerate()
+	if id != "ord_000010-zzz" {
+		t.Errorf("Expected ID %q, got %q", "ord_000010-zzz", id)
+	}
+
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if parsed.Ticks != 62 || parsed.Random != "zzz" || !parsed.Time.Equal(fakeClockEpoch.Add(offset)) {
+		t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+	}
+
+	for _, invalid := range []string{"ord_000010zzzz", "abc_000010-zzz", "ord_000010-zz", "ord_00001!-zzz"} {
+		if _, err := gen.Parse(invalid); err == nil {
+			t.Errorf("Expected an error parsing %q, but got nil", invalid)
+		}
+	}
+}
+
+func Test_Layout_VariableTimeInMiddle(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithLayout(MustParseLayout("{rand:2}{time}{rand}")))
+
+	id := gen.MustGenerate()
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if parsed.Random != id[:2]+id[len(id)-5:] {
+		t.Errorf("Random got %q for ID %q", parsed.Random, id)
+	}
+	if time.Since(parsed.Time) > time.Minute {
+		t.Errorf("Expected a recent time, got %v", parsed.Time)
+	}
+}
+
+func Test_Layout_Grouping(t *testing.T) {
+	var offset time.Duration
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithLayout(MustParseLayout("x{time:5}{rand:3}")).
+		WithGrouping(" ", 3).
+		WithRandomSource(&sameByteReader{b: 123}), &offset))
+
+	id := gen.MustGenerate()
+	if id != "x00 000 zzz" {
+		t.Errorf("Expected ID %q, got %q", "x00 000 zzz", id)
+	}
+	if parsed, err := gen.Parse(id); err != nil || parsed.Random != "zzz" {
+		t.Errorf("Unexpected parse result for %q: %+v, %v", id, parsed, err)
+	}
+}
+
+func Test_Layout_TimeOverflow(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithLayout(MustParseLayout("{time:3}{rand}")))
+
+	if _, err := gen.Generate(); err == nil {
+		t.Error("Expected an error when the time component doesn't fit its width, but got nil")
+	}
+}
+
+func Test_Layout_Blocklist(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithLayout(MustParseLayout("xy{rand:2}")).
+		WithRandomSource(&sequenceReader{bytes: []byte{61, 61, 10}}).
+		WithBlocklist("xy", "yz"))
+
+	// "xy" lies within the literal, so can't be avoided, but "yz" overlaps the random segment.
+	if id := gen.MustGenerate(); id != "xyAA" {
+		t.Errorf("Expected ID %q, got %q", "xyAA", id)
+	}
+	if regenerated := gen.Stats().Regenerated; regenerated != 1 {
+		t.Errorf("Expected 1 regeneration, got %d", regenerated)
+	}
+}
+
+func Test_Layout_EntropyBits(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithAlphabet(Base16LowerAlphabet).
+		WithLayout(MustParseLayout("{rand:2}{time}{rand:3}")))
+
+	if bits := gen.EntropyBits(); math.Abs(bits-20) > 1e-9 {
+		t.Errorf("EntropyBits() got %f, want 20", bits)
+	}
+}
+
+func Test_Layout_CollationSafe(t *testing.T) {
+	layout := MustParseLayout("{time}{rand:5}")
+	gen := MustNewGenerator(NewConfig().WithLayout(layout).WithCollationSafe(true))
+
+	if bits, minBits := gen.EntropyBits(), 5*math.Log2(62); bits < minBits {
+		t.Errorf("Expected at least %.1f bits after folding, got %.1f", minBits, bits)
+	}
+	if random := gen.MustParse(gen.MustGenerate()).Random; len(random) != 6 {
+		t.Errorf("Expected the random segment to grow to 6 characters, got %q", random)
+	}
+	if layout.segments[1].width != 5 {
+		t.Errorf("Expected the config's layout to be left unchanged, got width %d", layout.segments[1].width)
+	}
+}
+
+func Test_Layout_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Word List", NewConfig().WithWordList(Words256, "-").WithLayout(MustParseLayout("{time}{rand}"))},
+		{"Literal Contains Separator", NewConfig().WithGrouping("-", 4).WithLayout(MustParseLayout("{time}-{rand}"))},
+		{"Two Time Segments", NewConfig().WithLayout(MustParseLayout("{time:8}{time}"))},
+		{"Two Variable Segments", NewConfig().WithNumRandomChars(0).WithLayout(NewLayout().Time(0).Literal("-").Time(0))},
+		{"No Tick Size", NewConfig().WithTickSize(0).WithLayout(MustParseLayout("{time}{rand}"))},
+		{"Only Literals", NewConfig().WithLayout(MustParseLayout("abc"))},
+		{"Negative Width", NewConfig().WithLayout(NewLayout().Time(0).Random(-1))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
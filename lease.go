@@ -0,0 +1,204 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LeaseConfig holds the configuration for acquiring a NodeLease.
+type LeaseConfig struct {
+	dir               string        // Directory shared by all nodes, holding lock and heartbeat files.
+	maxNodeID         uint64        // Largest node ID which may be claimed.
+	heartbeatInterval time.Duration // How often the heartbeat file is renewed.
+	staleAfter        time.Duration // Age after which a heartbeat is considered abandoned.
+}
+
+// NodeLease is a claimed node ID, held via a file lock in a shared directory until released.
+// While held, a heartbeat file is renewed in the background, so operators can see which node IDs
+// are live. Leases suit hosts without a coordination service, e.g. several processes on a bare-metal
+// host. Note file locks are not reliable on all network filesystems.
+type NodeLease struct {
+	config   LeaseConfig
+	nodeID   uint64
+	lockFile *os.File
+
+	stop        chan struct{}
+	done        chan struct{}
+	releaseOnce sync.Once
+
+	mu  sync.Mutex
+	err error // Most recent heartbeat error, if any.
+}
+
+// NewLeaseConfig returns a default lease configuration for the given directory:
+// - maxNodeID: MaxNodeID(DefaultAlphabet, 2)
+// - heartbeatInterval: 10 seconds
+// - staleAfter: 3 heartbeat intervals
+func NewLeaseConfig(dir string) LeaseConfig {
+	return LeaseConfig{
+		dir:               dir,
+		maxNodeID:         MaxNodeID(DefaultAlphabet, 2),
+		heartbeatInterval: 10 * time.Second,
+	}
+}
+
+// WithMaxNodeID sets the largest node ID which may be claimed. It should fit in the width of the
+// generator's node segment, e.g. MaxNodeID(alphabet, width).
+func (c LeaseConfig) WithMaxNodeID(maxNodeID uint64) LeaseConfig {
+	c.maxNodeID = maxNodeID
+	return c
+}
+
+// WithHeartbeatInterval sets how often the heartbeat file is renewed.
+func (c LeaseConfig) WithHeartbeatInterval(heartbeatInterval time.Duration) LeaseConfig {
+	c.heartbeatInterval = heartbeatInterval
+	return c
+}
+
+// WithStaleAfter sets the age after which an unlocked node's heartbeat is considered abandoned, making the
+// node ID claimable again. This protects node IDs whose holder can't be seen via the lock, e.g. a process
+// which has only just died. Defaults to 3 heartbeat intervals.
+func (c LeaseConfig) WithStaleAfter(staleAfter time.Duration) LeaseConfig {
+	c.staleAfter = staleAfter
+	return c
+}
+
+// AcquireNodeLease claims the lowest free node ID in the configured directory, creating it if needed.
+// A node ID is free if its lock file isn't locked, and its heartbeat is missing or stale.
+// The lease must be released with Release when the node shuts down.
+func AcquireNodeLease(config LeaseConfig) (*NodeLease, error) {
+	if config.heartbeatInterval <= 0 {
+		return nil, errors.New("heartbeat interval must be positive")
+	}
+	if config.staleAfter == 0 {
+		config.staleAfter = 3 * config.heartbeatInterval
+	}
+	if config.staleAfter < config.heartbeatInterval {
+		return nil, errors.New("stale-after duration cannot be shorter than the heartbeat interval")
+	}
+
+	err := os.MkdirAll(config.dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lease directory: %w", err)
+	}
+
+	for id := uint64(0); ; id++ {
+		lease, err := tryAcquireNodeLease(config, id)
+		if err != nil {
+			return nil, err
+		}
+		if lease != nil {
+			go lease.renew()
+			return lease, nil
+		}
+
+		if id == config.maxNodeID {
+			return nil, fmt.Errorf("no free node ID in %s (max %d)", config.dir, config.maxNodeID)
+		}
+	}
+}
+
+// tryAcquireNodeLease claims the given node ID if it's free, returning nil if it isn't.
+func tryAcquireNodeLease(config LeaseConfig, id uint64) (*NodeLease, error) {
+	lockFile, locked, err := lockFile(filepath.Join(config.dir, fmt.Sprintf("node-%d.lock", id)))
+	if err != nil || !locked {
+		return nil, err
+	}
+
+	lease := &NodeLease{
+		config:   config,
+		nodeID:   id,
+		lockFile: lockFile,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if lease.heartbeatIsFresh() {
+		return nil, unlockFile(lockFile)
+	}
+	if err := lease.writeHeartbeat(); err != nil {
+		_ = unlockFile(lockFile)
+		return nil, err
+	}
+	return lease, nil
+}
+
+// NodeID returns the claimed node ID.
+func (l *NodeLease) NodeID() uint64 {
+	return l.nodeID
+}
+
+// Err returns the most recent error encountered while renewing the heartbeat, or nil.
+func (l *NodeLease) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Release stops renewing the heartbeat, removes it, and unlocks the node ID so it can be claimed
+// by another node. It's safe to call more than once.
+func (l *NodeLease) Release() error {
+	var err error
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		removeErr := os.Remove(l.heartbeatPath())
+		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			err = fmt.Errorf("failed to remove heartbeat: %w", removeErr)
+		}
+		// The lock file itself is left in place, as removing it would race with other nodes locking it.
+		if unlockErr := unlockFile(l.lockFile); unlockErr != nil && err == nil {
+			err = fmt.Errorf("failed to unlock node ID: %w", unlockErr)
+		}
+	})
+	return err
+}
+
+// WithNodeLease sets the node ID to the one claimed by the lease, encoded as width characters.
+// See WithNodeID.
+func (c Config) WithNodeLease(lease *NodeLease, width int) Config {
+	return c.WithNodeID(lease.NodeID(), width)
+}
+
+func (l *NodeLease) heartbeatPath() string {
+	return filepath.Join(l.config.dir, fmt.Sprintf("node-%d.heartbeat", l.nodeID))
+}
+
+// heartbeatIsFresh reports whether the node ID's heartbeat was renewed recently.
+func (l *NodeLease) heartbeatIsFresh() bool {
+	info, err := os.Stat(l.heartbeatPath())
+	return err == nil && time.Since(info.ModTime()) < l.config.staleAfter
+}
+
+// writeHeartbeat (re)writes the heartbeat file, recording who holds the node ID and when.
+func (l *NodeLease) writeHeartbeat() error {
+	content := fmt.Sprintf("pid=%d\ntime=%s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339Nano))
+	err := os.WriteFile(l.heartbeatPath(), []byte(content), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	return nil
+}
+
+// renew periodically rewrites the heartbeat until the lease is released.
+func (l *NodeLease) renew() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.config.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			err := l.writeHeartbeat()
+			l.mu.Lock()
+			l.err = err
+			l.mu.Unlock()
+		}
+	}
+}
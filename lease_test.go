@@ -0,0 +1,212 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package flexid
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_NodeLease_ClaimsDistinctIDs(t *testing.T) {
+	dir := t.TempDir()
+	config := NewLeaseConfig(dir).WithMaxNodeID(2)
+
+	var leases []*NodeLease
+	for i := 0; i < 3; i++ {
+		lease, err := AcquireNodeLease(config)
+		if err != nil {
+			t.Fatalf("AcquireNodeLease #%d failed: %v", i, err)
+		}
+		defer lease.Release()
+		if lease.NodeID() != uint64(i) {
+			t.Errorf("Expected node ID %d, got %d", i, lease.NodeID())
+		}
+		leases = append(leases, lease)
+	}
+
+	if _, err := AcquireNodeLease(config); err == nil {
+		t.Error("Expected an error when all node IDs are claimed, but got nil")
+	}
+
+	// Releasing a lease frees its node ID for the next claim.
+	if err := leases[1].Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := leases[1].Release(); err != nil {
+		t.Errorf("Expected releasing twice to be a no-op, got: %v", err)
+	}
+	lease, err := AcquireNodeLease(config)
+	if err != nil {
+		t.Fatalf("AcquireNodeLease after release failed: %v", err)
+	}
+	defer lease.Release()
+	if lease.NodeID() != 1 {
+		t.Errorf("Expected released node ID 1 to be reclaimed, got %d", lease.NodeID())
+	}
+}
+
+func Test_NodeLease_Heartbeat(t *testing.T) {
+	dir := t.TempDir()
+	lease, err := AcquireNodeLease(NewLeaseConfig(dir).WithHeartbeatInterval(10 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+
+	heartbeat := filepath.Join(dir, "node-0.heartbeat")
+	first, err := os.Stat(heartbeat)
+	if err != nil {
+		t.Fatalf("Expected heartbeat file: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	second, err := os.Stat(heartbeat)
+	if err != nil {
+		t.Fatalf("Expected heartbeat file: %v", err)
+	}
+	if !second.ModTime().After(first.ModTime()) {
+		t.Error("Expected heartbeat to be renewed")
+	}
+	if err := lease.Err(); err != nil {
+		t.Errorf("Unexpected heartbeat error: %v", err)
+	}
+
+	if err := lease.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(heartbeat); !os.IsNotExist(err) {
+		t.Errorf("Expected heartbeat file to be removed on release, got: %v", err)
+	}
+}
+
+func Test_NodeLease_SkipsFreshHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	config := NewLeaseConfig(dir).WithHeartbeatInterval(time.Hour)
+
+	// Node 0 is unlocked, but its holder was seen recently, e.g. it has only just died.
+	if err := os.WriteFile(filepath.Join(dir, "node-0.heartbeat"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lease, err := AcquireNodeLease(config)
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+	defer lease.Release()
+	if lease.NodeID() != 1 {
+		t.Errorf("Expected node ID 1 to be claimed, got %d", lease.NodeID())
+	}
+
+	// Once stale, node 0 may be claimed again.
+	stale := time.Now().Add(-4 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "node-0.heartbeat"), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	lease, err = AcquireNodeLease(config)
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+	defer lease.Release()
+	if lease.NodeID() != 0 {
+		t.Errorf("Expected stale node ID 0 to be claimed, got %d", lease.NodeID())
+	}
+}
+
+func Test_NodeLease_Generator(t *testing.T) {
+	lease, err := AcquireNodeLease(NewLeaseConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+	defer lease.Release()
+
+	gen := MustNewGenerator(NewConfig().WithNodeLease(lease, 2))
+	if parsed := gen.MustParse(gen.MustGenerate()); parsed.Node != lease.NodeID() {
+		t.Errorf("Expected node %d, got %d", lease.NodeID(), parsed.Node)
+	}
+}
+
+func Test_NodeLease_Validation(t *testing.T) {
+	dir := t.TempDir()
+	for _, config := range []LeaseConfig{
+		NewLeaseConfig(dir).WithHeartbeatInterval(0),
+		NewLeaseConfig(dir).WithStaleAfter(time.Second),
+	} {
+		if lease, err := AcquireNodeLease(config); err == nil {
+			lease.Release()
+			t.Errorf("Expected an error for config %+v, but got nil", config)
+		}
+	}
+}
+
+func Test_NodeLease_MultipleProcesses(t *testing.T) {
+	dir := t.TempDir()
+
+	const processes = 3
+	var ids []uint64
+	for i := 0; i < processes; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^Test_NodeLease_HelperProcess$")
+		cmd.Env = append(os.Environ(), "FLEXID_LEASE_HELPER_DIR="+dir)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			stdin.Close()
+			cmd.Wait()
+		}()
+
+		line, err := bufio.NewReader(stdout).ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read node ID from helper process: %v", err)
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(line), 10, 64)
+		if err != nil {
+			t.Fatalf("Helper process printed %q: %v", line, err)
+		}
+		ids = append(ids, id)
+	}
+
+	// This process should see all node IDs held by the helpers.
+	lease, err := AcquireNodeLease(NewLeaseConfig(dir))
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+	defer lease.Release()
+	ids = append(ids, lease.NodeID())
+
+	for i, id := range ids {
+		if id != uint64(i) {
+			t.Errorf("Expected processes to claim node IDs 0-%d in order, got %v", processes, ids)
+			break
+		}
+	}
+}
+
+// Test_NodeLease_HelperProcess is run as a subprocess by Test_NodeLease_MultipleProcesses. It holds a
+// lease and prints its node ID until its stdin is closed.
+func Test_NodeLease_HelperProcess(t *testing.T) {
+	dir := os.Getenv("FLEXID_LEASE_HELPER_DIR")
+	if dir == "" {
+		t.Skip("only run as a helper process")
+	}
+
+	lease, err := AcquireNodeLease(NewLeaseConfig(dir))
+	if err != nil {
+		t.Fatalf("AcquireNodeLease failed: %v", err)
+	}
+	defer lease.Release()
+
+	os.Stdout.WriteString(strconv.FormatUint(lease.NodeID(), 10) + "\n")
+	_, _ = io.Copy(io.Discard, os.Stdin)
+}
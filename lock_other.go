@@ -0,0 +1,26 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package flexid
+
+import (
+	"errors"
+	"os"
+)
+
+var errFileLocksUnsupported = errors.New("file locks are not supported on this platform")
+
+func lockFile(path string) (*os.File, bool, error) {
+	return nil, false, errFileLocksUnsupported
+}
+
+func unlockFile(file *os.File) error {
+	return errFileLocksUnsupported
+}
+
+func lockOpenFile(file *os.File) error {
+	return errFileLocksUnsupported
+}
+
+func unlockOpenFile(file *os.File) error {
+	return errFileLocksUnsupported
+}
@@ -0,0 +1,55 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile opens the file, creating it if needed, and tries to take an exclusive lock on it without
+// blocking. It reports false if another open file holds the lock, whether in this process or another.
+func lockFile(path string) (*os.File, bool, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		_ = file.Close()
+		return nil, false, nil
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, false, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return file, true, nil
+}
+
+// unlockFile releases the lock taken by lockFile and closes the file.
+func unlockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// lockOpenFile takes an exclusive lock on the open file, blocking until it's available.
+func lockOpenFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
+
+// unlockOpenFile releases the lock taken by lockOpenFile, leaving the file open.
+func unlockOpenFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
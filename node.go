@@ -0,0 +1,46 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WithNodeID adds a node segment between the time component and the random part, holding the given ID
+// encoded in the alphabet as exactly width characters. Giving each node (e.g. pod or worker) a distinct ID
+// guarantees IDs from different nodes never collide, rather than relying on the random part alone.
+// Custom layouts can position the node segment with {node}.
+func (c Config) WithNodeID(id uint64, width int) Config {
+	c.nodeID = id
+	c.nodeWidth = width
+	return c
+}
+
+// MaxNodeID returns the largest node ID which fits in the given number of characters of the alphabet.
+func MaxNodeID(alphabet string, width int) uint64 {
+	return maxEncodable(len(alphabet), width)
+}
+
+// maxEncodable returns the largest number which can be encoded in width digits of the given base.
+func maxEncodable(base int, width int) uint64 {
+	maxValue := uint64(1)
+	for i := 0; i < width; i++ {
+		if maxValue > math.MaxUint64/uint64(base) {
+			return math.MaxUint64
+		}
+		maxValue *= uint64(base)
+	}
+	return maxValue - 1
+}
+
+// validateNodeID ensures the node ID can be encoded in the given width.
+func validateNodeID(id uint64, width int, base int) error {
+	if width <= 0 {
+		return errors.New("node ID width must be positive")
+	}
+	maxID := maxEncodable(base, width)
+	if id > maxID {
+		return fmt.Errorf("node ID %d does not fit in %d characters (max %d)", id, width, maxID)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package flexid
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_NodeID(t *testing.T) {
+	offset := 62 * time.Second
+	config := withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithRandomSource(&sameByteReader{b: 123}), &offset)
+
+	testCases := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"Default Layout", config.WithNodeID(63, 3), "10011zzzzz"},
+		{"Custom Layout", config.WithNodeID(7, 2).WithLayout(MustParseLayout("{node}-{time:4}-{rand:2}")), "07-0010-zz"},
+		{"Explicit Width", config.WithNodeID(7, 2).WithLayout(MustParseLayout("{time}{node:4}")), "100007"},
+		{"No Time Component", config.WithTickSize(0).WithNodeID(10, 1), "Azzzzz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := MustNewGenerator(tc.config)
+
+			id := gen.MustGenerate()
+			if id != tc.expected {
+				t.Errorf("Expected ID %q, got %q", tc.expected, id)
+			}
+
+			parsed, err := gen.Parse(id)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", id, err)
+			}
+			if parsed.Node != tc.config.nodeID {
+				t.Errorf("Node got %d, want %d", parsed.Node, tc.config.nodeID)
+			}
+		})
+	}
+}
+
+func Test_NodeID_DistinguishesNodes(t *testing.T) {
+	// Identical time and random source, so IDs only differ by node.
+	var offset time.Duration
+	config := withFakeClock(NewConfig().WithRandomSource(&sameByteReader{b: 0}), &offset)
+
+	id1 := MustNewGenerator(config.WithNodeID(1, 2)).MustGenerate()
+	id2 := MustNewGenerator(config.WithNodeID(2, 2)).MustGenerate()
+	if id1 == id2 {
+		t.Errorf("Expected IDs from different nodes to differ, got %q twice", id1)
+	}
+}
+
+func Test_NodeID_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Too Large", NewConfig().WithAlphabet(Base16LowerAlphabet).WithNodeID(256, 2)},
+		{"Zero Width", NewConfig().WithNodeID(1, 0)},
+		{"Negative Width", NewConfig().WithNodeID(0, -1)},
+		{"Layout Without Node", NewConfig().WithNodeID(1, 2).WithLayout(MustParseLayout("{time}{rand}"))},
+		{"Node Without ID", NewConfig().WithLayout(MustParseLayout("{time}{node:2}{rand}"))},
+		{"Two Node Segments", NewConfig().WithNodeID(1, 2).WithLayout(MustParseLayout("{node}{time}{node}"))},
+		{"Word List", NewConfig().WithNodeID(1, 2).WithWordList(Words256, "-")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func Test_MaxNodeID(t *testing.T) {
+	testCases := []struct {
+		alphabet string
+		width    int
+		expected uint64
+	}{
+		{Base16LowerAlphabet, 2, 255},
+		{Base62Alphabet, 1, 61},
+		{Base62Alphabet, 3, 62*62*62 - 1},
+		{"01", 64, 1<<64 - 1},
+		{"01", 100, 1<<64 - 1},
+	}
+
+	for _, tc := range testCases {
+		if maxID := MaxNodeID(tc.alphabet, tc.width); maxID != tc.expected {
+			t.Errorf("MaxNodeID(%q, %d) = %d, want %d", tc.alphabet, tc.width, maxID, tc.expected)
+		}
+	}
+}
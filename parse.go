@@ -0,0 +1,95 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ParsedID holds the components decoded from an ID.
+type ParsedID struct {
+	Ticks    uint64    // Number of ticks since the epoch. Zero if there's no time component.
+	Time     time.Time // Start of the tick the ID was generated in. Zero if there's no time component.
+	Node     uint64    // The node ID. Zero if there's no node segment.
+	Sequence uint64    // The sequence number within the tick. Zero if there's no sequence segment.
+	Random   string    // The random part.
+}
+
+// Parse decodes an ID generated with the generator's configuration, returning an error if it doesn't
+// conform to it. Separators from WithGrouping are stripped before decoding.
+func (g *Generator) Parse(id string) (ParsedID, error) {
+	if g.config.words != nil {
+		return g.parseWords(id)
+	}
+	if g.segments != nil {
+		return g.parseLayout(id)
+	}
+	if g.config.uuidV7 {
+		return g.parseUUID(id)
+	}
+
+	id = g.ungroup(id)
+
+	timestampLen := len(id) - g.config.numRandomChars
+	if timestampLen < 0 || (g.config.tickSize > 0 && timestampLen == 0) {
+		return ParsedID{}, fmt.Errorf("ID %q is too short", id)
+	}
+	if g.config.tickSize <= 0 && timestampLen > 0 {
+		return ParsedID{}, fmt.Errorf("ID %q is too long", id)
+	}
+
+	var parsed ParsedID
+	if g.config.tickSize > 0 {
+		ticks, err := g.decodeBaseN(id[:timestampLen])
+		if err != nil {
+			return ParsedID{}, fmt.Errorf("invalid time component in ID %q: %w", id, err)
+		}
+		parsed.Ticks = ticks
+		parsed.Time, err = g.tickTime(ticks)
+		if err != nil {
+			return ParsedID{}, err
+		}
+	}
+
+	parsed.Random = id[timestampLen:]
+	err := g.validateRandomPart(parsed.Random)
+	if err != nil {
+		return ParsedID{}, fmt.Errorf("invalid random part in ID %q: %w", id, err)
+	}
+
+	return parsed, nil
+}
+
+// validateRandomPart ensures the random part could have been generated by the generator's random strategy.
+func (g *Generator) validateRandomPart(random string) error {
+	if g.config.randomStrategy == RandomPronounceable {
+		if !isPronounceable(random) {
+			return errors.New("expected alternating consonants and vowels")
+		}
+		return nil
+	}
+	for i := 0; i < len(random); i++ {
+		if g.indexes[random[i]] < 0 {
+			return fmt.Errorf("character %q is not in the alphabet", random[i])
+		}
+	}
+	return nil
+}
+
+// tickTime returns the start time of the given tick.
+func (g *Generator) tickTime(ticks uint64) (time.Time, error) {
+	if ticks > uint64(math.MaxInt64/int64(g.config.tickSize)) {
+		return time.Time{}, errors.New("time component is too large to represent")
+	}
+	return g.config.epoch.Add(time.Duration(ticks) * g.config.tickSize), nil
+}
+
+// MustParse is like Parse, but panics if the ID is invalid.
+func (g *Generator) MustParse(id string) ParsedID {
+	parsed, err := g.Parse(id)
+	if err != nil {
+		panic("flexid: failed to parse ID: " + err.Error())
+	}
+	return parsed
+}
@@ -0,0 +1,84 @@
+package flexid
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parse_RoundTrip(t *testing.T) {
+	offset := 90*time.Minute + 1500*time.Millisecond
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithRandomSource(&sameByteReader{b: 123}), &offset))
+
+	id := gen.MustGenerate()
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+
+	if parsed.Ticks != 5401 {
+		t.Errorf("Ticks got %d, want 5401", parsed.Ticks)
+	}
+	if expected := fakeClockEpoch.Add(offset).Truncate(time.Second); !parsed.Time.Equal(expected) {
+		t.Errorf("Time got %v, want %v", parsed.Time, expected)
+	}
+	if parsed.Random != "zzzzz" {
+		t.Errorf("Random got %q, want %q", parsed.Random, "zzzzz")
+	}
+}
+
+func Test_Parse_NoTimeComponent(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithTickSize(0).WithNumRandomChars(8))
+
+	id := gen.MustGenerate()
+	parsed := gen.MustParse(id)
+	if parsed.Random != id || parsed.Ticks != 0 || !parsed.Time.IsZero() {
+		t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+	}
+
+	if _, err := gen.Parse(id + "0"); err == nil {
+		t.Error("Expected an error for an ID which is too long, but got nil")
+	}
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithAlphabet(Base16LowerAlphabet))
+
+	testCases := []struct {
+		name string
+		id   string
+	}{
+		{"Empty", ""},
+		{"Only Random Part", "abcde"},
+		{"Invalid Time Char", "1g2abcde"},
+		{"Invalid Random Char", "12abcdz"},
+		{"Overflow", "1ffffffffffffffffabcde"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if parsed, err := gen.Parse(tc.id); err == nil {
+				t.Errorf("Expected an error parsing %q, but got %+v", tc.id, parsed)
+			}
+		})
+	}
+}
+
+func Test_DecodeBaseN(t *testing.T) {
+	gen := MustNewGenerator(NewConfig())
+
+	for _, number := range []uint64{0, 1, 61, 62, 1234567890, 1<<64 - 1} {
+		encoded, err := gen.encodeBaseN(number)
+		if err != nil {
+			t.Fatalf("encodeBaseN(%d) failed: %v", number, err)
+		}
+		decoded, err := gen.decodeBaseN(encoded)
+		if err != nil {
+			t.Fatalf("decodeBaseN(%q) failed: %v", encoded, err)
+		}
+		if decoded != number {
+			t.Errorf("decodeBaseN(%q) = %d, want %d", encoded, decoded, number)
+		}
+	}
+}
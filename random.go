@@ -0,0 +1,133 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// RandomStrategy determines how the random part of an ID is generated.
+type RandomStrategy int
+
+const (
+	// RandomAlphabet picks each random character uniformly from the alphabet. This is the default.
+	RandomAlphabet RandomStrategy = iota
+	// RandomPronounceable alternates consonants and vowels, starting with a consonant, e.g. "kobaturi".
+	// The time component is still encoded in the alphabet.
+	RandomPronounceable
+)
+
+// Characters used by RandomPronounceable. Consonants which are easily misheard or have
+// ambiguous pronunciations (c, q, w, x, y) are excluded.
+const (
+	pronounceableConsonants = "bdfghjklmnprstvz"
+	pronounceableVowels     = "aeiou"
+)
+
+// WithRandomStrategy sets how the random part of IDs is generated.
+// Use EntropyBits to compare the collision resistance of different strategies.
+func (c Config) WithRandomStrategy(strategy RandomStrategy) Config {
+	c.randomStrategy = strategy
+	return c
+}
+
+// validateRandomStrategy ensures the strategy is known and compatible with the rest of the config.
+func validateRandomStrategy(config Config) error {
+	switch config.randomStrategy {
+	case RandomAlphabet:
+		return nil
+	case RandomPronounceable:
+		if config.words != nil {
+			return errors.New("pronounceable random strategy cannot be combined with a word list")
+		}
+		if config.separator != "" && strings.ContainsAny(config.separator, pronounceableConsonants+pronounceableVowels) {
+			return fmt.Errorf("separator %q contains characters used by the pronounceable random strategy", config.separator)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown random strategy: %d", config.randomStrategy)
+	}
+}
+
+// EntropyBits returns the number of bits of entropy in the random part of each ID.
+func (g *Generator) EntropyBits() float64 {
+	if g.config.uuidV7 {
+		return uuidRandomBits
+	}
+	bits := 0.0
+	for _, n := range g.randomWidths() {
+		if g.config.randomStrategy == RandomPronounceable {
+			consonants := (n + 1) / 2
+			vowels := n / 2
+			bits += float64(consonants)*math.Log2(float64(len(pronounceableConsonants))) +
+				float64(vowels)*math.Log2(float64(len(pronounceableVowels)))
+		} else {
+			bits += float64(n) * math.Log2(float64(g.base))
+		}
+	}
+	return bits
+}
+
+// randomWidths returns the length of each random part of an ID.
+func (g *Generator) randomWidths() []int {
+	if g.segments == nil {
+		return []int{g.config.numRandomChars}
+	}
+	var widths []int
+	for _, s := range g.segments {
+		if s.kind == segmentRandom {
+			widths = append(widths, s.width)
+		}
+	}
+	return widths
+}
+
+// generatePronounceable generates a cryptographically secure random string of alternating consonants
+// and vowels, avoiding modulo bias via rejection sampling.
+func (g *Generator) generatePronounceable(length int) (string, error) {
+	if length == 0 {
+		return "", nil
+	}
+
+	bytes := make([]byte, length)
+	randomBytes := make([]byte, length)
+	for i := 0; i < length; {
+		if _, err := io.ReadFull(g.config.randomSource, randomBytes); err != nil {
+			return "", errors.New("failed to read random bytes: " + err.Error())
+		}
+
+		for _, randomByte := range randomBytes {
+			chars := pronounceableCharsAt(i)
+			if int(randomByte) > (256/len(chars))*len(chars)-1 {
+				continue // Discard biased byte
+			}
+			bytes[i] = chars[int(randomByte)%len(chars)]
+			i++
+			if i == length {
+				break
+			}
+		}
+	}
+
+	return string(bytes), nil
+}
+
+// pronounceableCharsAt returns the characters allowed at the given position of a pronounceable string.
+func pronounceableCharsAt(i int) string {
+	if i%2 == 0 {
+		return pronounceableConsonants
+	}
+	return pronounceableVowels
+}
+
+// isPronounceable reports whether s alternates consonants and vowels as generated by generatePronounceable.
+func isPronounceable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(pronounceableCharsAt(i), s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package flexid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_RandomPronounceable(t *testing.T) {
+	var offset time.Duration
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithNumRandomChars(4).
+		WithRandomStrategy(RandomPronounceable).
+		// 255 is out of range for vowels, so is discarded.
+		WithRandomSource(&sequenceReader{bytes: []byte{0, 255, 1, 2, 3}}), &offset))
+
+	id := gen.MustGenerate()
+	if id != "0befo" {
+		t.Errorf("Expected ID %q, got %q", "0befo", id)
+	}
+
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if parsed.Random != "befo" {
+		t.Errorf("Random got %q, want %q", parsed.Random, "befo")
+	}
+
+	for _, invalid := range []string{"0bbbb", "0abab", "0BEFO"} {
+		if _, err := gen.Parse(invalid); err == nil {
+			t.Errorf("Expected an error parsing %q, but got nil", invalid)
+		}
+	}
+}
+
+func Test_RandomPronounceable_Random(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithTickSize(0).WithNumRandomChars(9).WithRandomStrategy(RandomPronounceable))
+
+	id1 := gen.MustGenerate()
+	id2 := gen.MustGenerate()
+	if id1 == id2 {
+		t.Errorf("Expected different IDs, but got identical %q", id1)
+	}
+	if len(id1) != 9 || !isPronounceable(id1) {
+		t.Errorf("Expected 9 alternating consonants and vowels, got %q", id1)
+	}
+}
+
+func Test_RandomPronounceable_CollationSafe(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithNumRandomChars(8).
+		WithRandomStrategy(RandomPronounceable).
+		WithCollationSafe(true))
+
+	if gen.config.alphabet != Base36Alphabet {
+		t.Errorf("alphabet got %q, want %q", gen.config.alphabet, Base36Alphabet)
+	}
+	random := gen.MustParse(gen.MustGenerate()).Random
+	if len(random) != 8 {
+		t.Errorf("Expected the pronounceable random part to keep 8 characters, got %q", random)
+	}
+}
+
+func Test_EntropyBits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		expected float64
+	}{
+		{"Default", NewConfig(), 5 * math.Log2(62)},
+		{"Base16", NewConfig().WithAlphabet(Base16LowerAlphabet).WithNumRandomChars(6), 24},
+		{"None", NewConfig().WithNumRandomChars(0), 0},
+		{"Pronounceable Even", NewConfig().WithNumRandomChars(8).WithRandomStrategy(RandomPronounceable), 4*4 + 4*math.Log2(5)},
+		{"Pronounceable Odd", NewConfig().WithNumRandomChars(3).WithRandomStrategy(RandomPronounceable), 2*4 + math.Log2(5)},
+		{"Word List", NewConfig().WithNumRandomChars(2).WithWordList(Words256, "-"), 16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bits := MustNewGenerator(tc.config).EntropyBits()
+			if math.Abs(bits-tc.expected) > 1e-9 {
+				t.Errorf("EntropyBits() got %f, want %f", bits, tc.expected)
+			}
+		})
+	}
+}
+
+func Test_RandomStrategy_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Unknown", NewConfig().WithRandomStrategy(RandomStrategy(42))},
+		{"Word List", NewConfig().WithWordList(Words256, "-").WithRandomStrategy(RandomPronounceable)},
+		{"Separator Clash", NewConfig().WithAlphabet(Base16UpperAlphabet).WithGrouping("a", 4).WithRandomStrategy(RandomPronounceable)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package flexid
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Registry records claimed IDs, for uniqueness across every generator sharing it. A real deployment
+// would typically back it with a key-value store supporting an atomic "set if absent".
+type Registry interface {
+	// Claim records the ID as taken, reporting false if it had already been claimed.
+	Claim(ctx context.Context, id string) (bool, error)
+}
+
+// WithRegistry makes the generator claim each ID in the registry before returning it, regenerating the
+// random part if it's already claimed. This guarantees uniqueness wherever the registry is shared, at the
+// cost of a registry call per ID. Use GenerateContext to pass a context on to the registry. Conflicts are
+// counted in Stats.Conflicts.
+func (c Config) WithRegistry(registry Registry) Config {
+	c.registry = registry
+	return c
+}
+
+// MemoryRegistry is an in-memory Registry, for generators within one process, or tests. It remembers
+// every claimed ID, so grows without bound.
+type MemoryRegistry struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// NewMemoryRegistry returns an empty MemoryRegistry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{ids: make(map[string]struct{})}
+}
+
+// Claim records the ID, reporting false if it was already claimed.
+func (r *MemoryRegistry) Claim(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ids[id]; ok {
+		return false, nil
+	}
+	r.ids[id] = struct{}{}
+	return true, nil
+}
+
+// FileRegistry is a Registry storing each claimed ID as a file in a directory, which may be shared
+// between processes. It's intended for tests rather than production use.
+type FileRegistry struct {
+	dir string
+}
+
+// NewFileRegistry returns a FileRegistry in the given directory, creating it if needed.
+func NewFileRegistry(dir string) (*FileRegistry, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	return &FileRegistry{dir: dir}, nil
+}
+
+// Claim records the ID by exclusively creating its file, reporting false if it already exists.
+func (r *FileRegistry) Claim(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// IDs are hex-encoded, as they may contain characters which aren't valid in file names.
+	file, err := os.OpenFile(filepath.Join(r.dir, hex.EncodeToString([]byte(id))), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim %q: %w", id, err)
+	}
+	return true, file.Close()
+}
+
+// rejection is why a random part was discarded.
+type rejection int
+
+const (
+	accepted rejection = iota
+	rejectBlocked
+	rejectDuplicate
+	rejectConflict
+)
+
+// claimUnique claims the ID made of the given parts against the recent IDs and the registry, if
+// configured, returning why it was rejected, or accepted.
+func (g *Generator) claimUnique(ctx context.Context, ticks uint64, parts ...string) (rejection, error) {
+	if g.config.dedupLimit == 0 && g.config.registry == nil {
+		return accepted, nil
+	}
+
+	id := strings.Join(parts, "")
+	if !g.claimRecent(ticks, id) {
+		return rejectDuplicate, nil
+	}
+	if g.config.registry != nil {
+		claimed, err := g.config.registry.Claim(ctx, g.group(id))
+		if err != nil {
+			return accepted, fmt.Errorf("failed to claim ID in registry: %w", err)
+		}
+		if !claimed {
+			return rejectConflict, nil
+		}
+	}
+	return accepted, nil
+}
+
+// rejectRandom records that a random part was rejected for the given reason, returning an error if it was
+// the last attempt, or if the ID has no random characters to regenerate.
+func (g *Generator) rejectRandom(attempt int, reason rejection, hasRandom bool) error {
+	if !hasRandom {
+		return fmt.Errorf("%s: the ID has no random characters to regenerate", rejectionFailure(reason))
+	}
+	if attempt == maxRandomAttempts {
+		return fmt.Errorf("%s after %d attempts", rejectionFailure(reason), attempt)
+	}
+
+	switch reason {
+	case rejectBlocked:
+		g.regenerated.Add(1)
+	case rejectDuplicate:
+		g.duplicates.Add(1)
+	default:
+		g.conflicts.Add(1)
+	}
+	return nil
+}
+
+// rejectionFailure describes the failure to generate an ID which avoids the given reason for rejection.
+func rejectionFailure(reason rejection) string {
+	switch reason {
+	case rejectBlocked:
+		return "failed to generate an ID without blocked words"
+	case rejectDuplicate:
+		return "failed to generate an ID not issued earlier in the tick"
+	default:
+		return "failed to generate an ID not already claimed"
+	}
+}
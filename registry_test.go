@@ -0,0 +1,151 @@
+package flexid
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingRegistry is a Registry whose claims always fail.
+type failingRegistry struct{}
+
+func (failingRegistry) Claim(ctx context.Context, id string) (bool, error) {
+	return false, errors.New("registry unavailable")
+}
+
+func Test_Registry_RetriesConflicts(t *testing.T) {
+	registry := NewMemoryRegistry()
+	config, _ := fakeClockTestConfig("01", 1)
+	first := MustNewGenerator(config.WithRandomSource(&sameByteReader{b: 0}).WithRegistry(registry))
+	second := MustNewGenerator(config.WithRandomSource(&sequenceReader{bytes: []byte{0, 1}}).WithRegistry(registry))
+
+	a := first.MustGenerate()
+	b := second.MustGenerate()
+	if a == b {
+		t.Errorf("Expected generators sharing a registry to issue distinct IDs, got %q twice", a)
+	}
+	if stats := second.Stats(); stats.Generated != 1 || stats.Conflicts != 1 {
+		t.Errorf("Expected 1 generated and 1 conflict, got %+v", stats)
+	}
+}
+
+func Test_Registry_Exhausted(t *testing.T) {
+	registry := NewMemoryRegistry()
+	config, _ := fakeClockTestConfig("01", 1)
+	gen := MustNewGenerator(config.WithRandomSource(&sequenceReader{bytes: []byte{0, 1}}).WithRegistry(registry))
+
+	gen.MustGenerate()
+	gen.MustGenerate()
+	_, err := gen.Generate()
+	if err == nil || !strings.Contains(err.Error(), "not already claimed") {
+		t.Errorf("Expected an error once every ID is claimed, got %v", err)
+	}
+}
+
+func Test_Registry_NoRandomChars(t *testing.T) {
+	registry := NewMemoryRegistry()
+	offset := time.Hour
+	gen := MustNewGenerator(withFakeClock(NewConfig().WithNumRandomChars(0).WithRegistry(registry), &offset))
+
+	id := gen.MustGenerate()
+	if claimed, _ := registry.Claim(context.Background(), id); claimed {
+		t.Errorf("Expected the generated ID %q to have been claimed", id)
+	}
+	_, err := gen.Generate()
+	if err == nil || !strings.Contains(err.Error(), "no random characters") {
+		t.Errorf("Expected an error rather than a second unclaimed %q, got %v", id, err)
+	}
+}
+
+func Test_Registry_LayoutNoRandomChars(t *testing.T) {
+	registry := NewMemoryRegistry()
+	offset := time.Hour
+	gen := MustNewGenerator(withFakeClock(NewConfig().WithLayout(MustParseLayout("{time}-x")).WithRegistry(registry), &offset))
+
+	gen.MustGenerate()
+	_, err := gen.Generate()
+	if err == nil || !strings.Contains(err.Error(), "no random characters") {
+		t.Errorf("Expected an error without retrying, got %v", err)
+	}
+}
+
+func Test_Registry_ClaimsGroupedID(t *testing.T) {
+	registry := NewMemoryRegistry()
+	gen := MustNewGenerator(NewConfig().WithGrouping("-", 4).WithRegistry(registry))
+
+	id := gen.MustGenerate()
+	if claimed, _ := registry.Claim(context.Background(), id); claimed {
+		t.Errorf("Expected the generated ID %q to have been claimed", id)
+	}
+}
+
+func Test_Registry_Layout(t *testing.T) {
+	registry := NewMemoryRegistry()
+	config, _ := fakeClockTestConfig("01", 1)
+	gen := MustNewGenerator(config.
+		WithLayout(MustParseLayout("{time}-{rand:1}")).
+		WithRandomSource(&sequenceReader{bytes: []byte{0, 0, 1}}).
+		WithRegistry(registry))
+
+	first := gen.MustGenerate()
+	second := gen.MustGenerate()
+	if first == second || gen.Stats().Conflicts != 1 {
+		t.Errorf("Expected a conflict to be regenerated, got %q and %q with %+v", first, second, gen.Stats())
+	}
+}
+
+func Test_Registry_Errors(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithRegistry(failingRegistry{}))
+	_, err := gen.Generate()
+	if err == nil || !strings.Contains(err.Error(), "registry unavailable") {
+		t.Errorf("Expected the registry's error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gen = MustNewGenerator(NewConfig().WithRegistry(NewMemoryRegistry()))
+	_, err = gen.GenerateContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func Test_MemoryRegistry(t *testing.T) {
+	registry := NewMemoryRegistry()
+	ctx := context.Background()
+
+	if claimed, err := registry.Claim(ctx, "abc"); !claimed || err != nil {
+		t.Errorf("Expected the first claim to succeed, got %v, %v", claimed, err)
+	}
+	if claimed, err := registry.Claim(ctx, "abc"); claimed || err != nil {
+		t.Errorf("Expected the second claim to fail, got %v, %v", claimed, err)
+	}
+	if claimed, err := registry.Claim(ctx, "abd"); !claimed || err != nil {
+		t.Errorf("Expected a claim of another ID to succeed, got %v, %v", claimed, err)
+	}
+}
+
+func Test_FileRegistry(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := NewFileRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewFileRegistry failed: %v", err)
+	}
+	second, err := NewFileRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewFileRegistry failed: %v", err)
+	}
+
+	// IDs may contain characters which aren't valid in file names.
+	const id = "a/b.c"
+	if claimed, err := first.Claim(ctx, id); !claimed || err != nil {
+		t.Errorf("Expected the first claim to succeed, got %v, %v", claimed, err)
+	}
+	if claimed, err := second.Claim(ctx, id); claimed || err != nil {
+		t.Errorf("Expected a claim via another registry in the same directory to fail, got %v, %v", claimed, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package requestid provides net/http middleware tagging each request with a FlexID, so it can be traced
+// through handlers, logs and downstream services.
+//
+//	gen := flexid.MustNewGenerator(flexid.NewConfig())
+//	http.ListenAndServe(":8080", requestid.Middleware(gen)(mux))
+//
+// Handlers retrieve the ID with FromContext, and LogHandler adds it to log records.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amterp/flexid"
+)
+
+// Header is the header carrying the request ID, on both requests and responses.
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// Middleware returns middleware giving each request an ID. An incoming X-Request-ID is kept if it parses
+// under the generator's config, so IDs minted upstream carry through; otherwise, including when it's
+// absent, a new ID is generated. The ID is stored in the request's context and echoed in the response's
+// X-Request-ID header. If an ID can't be generated, the request fails with 500 Internal Server Error.
+func Middleware(gen *flexid.Generator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(Header)
+			if id == "" || !valid(gen, id) {
+				var err error
+				id, err = gen.GenerateContext(r.Context())
+				if err != nil {
+					http.Error(w, "failed to generate request ID", http.StatusInternalServerError)
+					return
+				}
+			}
+
+			w.Header().Set(Header, id)
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+		})
+	}
+}
+
+// valid reports whether the ID could have been generated with the generator's config.
+func valid(gen *flexid.Generator, id string) bool {
+	_, err := gen.Parse(id)
+	return err == nil
+}
@@ -0,0 +1,102 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amterp/flexid"
+)
+
+// serve sends a request with the given X-Request-ID, or none if empty, through the middleware, returning
+// the ID seen by the handler and the response.
+func serve(t *testing.T, gen *flexid.Generator, incoming string) (string, *httptest.ResponseRecorder) {
+	t.Helper()
+	var seen string
+	handler := Middleware(gen)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := FromContext(r.Context())
+		if !ok {
+			t.Error("Expected a request ID in the context")
+		}
+		seen = id
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if incoming != "" {
+		req.Header.Set(Header, incoming)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return seen, rec
+}
+
+func Test_Middleware_GeneratesID(t *testing.T) {
+	gen := flexid.MustNewGenerator(flexid.NewConfig())
+
+	seen, rec := serve(t, gen, "")
+	if _, err := gen.Parse(seen); err != nil {
+		t.Errorf("Expected a generated ID, got %q: %v", seen, err)
+	}
+	if echoed := rec.Header().Get(Header); echoed != seen {
+		t.Errorf("Expected the response header %q, got %q", seen, echoed)
+	}
+
+	other, _ := serve(t, gen, "")
+	if other == seen {
+		t.Errorf("Expected a new ID per request, got %q twice", seen)
+	}
+}
+
+func Test_Middleware_KeepsValidID(t *testing.T) {
+	gen := flexid.MustNewGenerator(flexid.NewConfig())
+	incoming := gen.MustGenerate()
+
+	seen, rec := serve(t, gen, incoming)
+	if seen != incoming || rec.Header().Get(Header) != incoming {
+		t.Errorf("Expected the incoming ID %q to be kept, got %q (echoed %q)", incoming, seen,
+			rec.Header().Get(Header))
+	}
+}
+
+func Test_Middleware_ReplacesInvalidID(t *testing.T) {
+	gen := flexid.MustNewGenerator(flexid.NewConfig())
+
+	for _, incoming := range []string{"not-an-id!", "<script>", "abc"} {
+		seen, rec := serve(t, gen, incoming)
+		if seen == incoming {
+			t.Errorf("Expected the invalid ID %q to be replaced", incoming)
+		}
+		if _, err := gen.Parse(seen); err != nil || rec.Header().Get(Header) != seen {
+			t.Errorf("Expected a generated ID in place of %q, got %q: %v", incoming, seen, err)
+		}
+	}
+}
+
+func Test_Middleware_GenerateError(t *testing.T) {
+	// A clock before the epoch makes Generate fail.
+	gen := flexid.MustNewGenerator(flexid.NewConfig().
+		WithEpoch(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)).
+		WithTimeProvider(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }))
+
+	handler := Middleware(gen)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the handler not to be called")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get(Header) != "" {
+		t.Errorf("Expected 500 without a request ID, got %d (%q)", rec.Code, rec.Header().Get(Header))
+	}
+}
+
+func Test_Context(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no request ID in an empty context")
+	}
+
+	ctx := NewContext(context.Background(), "abc123")
+	if id, ok := FromContext(ctx); !ok || id != "abc123" {
+		t.Errorf("Expected abc123, got %q (%v)", id, ok)
+	}
+}
@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogKey is the attribute key under which LogHandler records the request ID.
+const LogKey = "request_id"
+
+// LogHandler is an slog.Handler adding the request ID carried by each record's context, if any, to the
+// record before passing it on. Log with the request's context, e.g. logger.InfoContext(r.Context(), ...), for
+// the ID to be found. Like any attribute added when a record is handled, it's qualified by groups opened with
+// WithGroup.
+type LogHandler struct {
+	next slog.Handler
+}
+
+// NewLogHandler returns a handler adding request IDs to records before passing them to next.
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at the level.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the request ID to the record, then passes it to the wrapped handler.
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := FromContext(ctx); ok {
+		record = record.Clone()
+		record.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a LogHandler wrapping the wrapped handler with the attributes.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a LogHandler wrapping the wrapped handler with the group.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
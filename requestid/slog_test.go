@@ -0,0 +1,64 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amterp/flexid"
+)
+
+// newTestLogger returns a logger writing text without timestamps to buf, through a LogHandler.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(NewLogHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+}
+
+func Test_LogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	logger.InfoContext(NewContext(context.Background(), "abc123"), "hello", "n", 1)
+	logger.InfoContext(context.Background(), "no ID")
+	logger.With("user", "ann").WithGroup("g").InfoContext(NewContext(context.Background(), "def456"), "grouped", "n", 2)
+
+	expected := "level=INFO msg=hello n=1 request_id=abc123\n" +
+		"level=INFO msg=\"no ID\"\n" +
+		"level=INFO msg=grouped user=ann g.n=2 g.request_id=def456\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected:\n%sGot:\n%s", expected, got)
+	}
+}
+
+func Test_LogHandler_Enabled(t *testing.T) {
+	handler := NewLogHandler(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if handler.Enabled(context.Background(), slog.LevelInfo) || !handler.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected Enabled to defer to the wrapped handler")
+	}
+}
+
+func Test_LogHandler_WithMiddleware(t *testing.T) {
+	gen := flexid.MustNewGenerator(flexid.NewConfig())
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	handler := Middleware(gen)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handled")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := "level=INFO msg=handled request_id=" + rec.Header().Get(Header) + "\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected:\n%sGot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,105 @@
+package flexid
+
+import (
+	"errors"
+	"time"
+)
+
+// OverflowPolicy determines what happens when a tick's sequence numbers are exhausted.
+type OverflowPolicy int
+
+const (
+	// OverflowWait blocks until the next tick, when the sequence resets.
+	OverflowWait OverflowPolicy = iota
+	// OverflowBorrow moves on to the next tick early, without waiting for the clock. Subsequent IDs keep
+	// using the borrowed tick until the clock catches up, so IDs stay ordered but may be slightly ahead.
+	OverflowBorrow
+	// OverflowError returns ErrSequenceExhausted.
+	OverflowError
+)
+
+// ErrSequenceExhausted is returned by Generate when a tick's sequence numbers are exhausted and the
+// overflow policy is OverflowError.
+var ErrSequenceExhausted = errors.New("sequence exhausted for the current tick")
+
+// WithSequence adds a sequence segment of exactly width characters after the time component (and node
+// segment, if any). The sequence counts up from zero within each tick, so IDs from one generator are
+// guaranteed unique and ordered without relying on randomness. The policy decides what happens when
+// a tick runs out of sequence numbers. Custom layouts can position the sequence segment with {seq}.
+// A generator with a sequence is stateful, and safe for concurrent use.
+func (c Config) WithSequence(width int, policy OverflowPolicy) Config {
+	c.sequenceWidth = width
+	c.overflowPolicy = policy
+	return c
+}
+
+// validateSequence ensures the sequence config is usable.
+func validateSequence(config Config) error {
+	if config.sequenceWidth < 0 {
+		return errors.New("sequence width cannot be negative")
+	}
+	if config.sequenceWidth == 0 {
+		if config.hostSequence != nil {
+			return errors.New("a host sequence requires a positive sequence width")
+		}
+		return nil
+	}
+	if config.tickSize <= 0 {
+		return errors.New("a sequence requires a positive tick size")
+	}
+	if config.overflowPolicy < OverflowWait || config.overflowPolicy > OverflowError {
+		return errors.New("unknown sequence overflow policy")
+	}
+	if config.maxDrift < 0 {
+		return errors.New("max drift cannot be negative")
+	}
+	return nil
+}
+
+// nextSequence returns the tick and sequence number to use for the next ID, given the current tick.
+// If the clock goes backwards, the last tick continues to be used, so IDs never go backwards either.
+func (g *Generator) nextSequence(ticks uint64, width int) (uint64, uint64, error) {
+	var lastTick, sequence uint64
+	err := g.updateSequence(func() error {
+		var err error
+		lastTick, sequence, err = g.advanceSequence(ticks, width)
+		return err
+	})
+	return lastTick, sequence, err
+}
+
+// advanceSequence is nextSequence, with the sequence state already locked.
+func (g *Generator) advanceSequence(ticks uint64, width int) (uint64, uint64, error) {
+	if !g.hasSequence || ticks > g.lastTick {
+		g.hasSequence = true
+		g.lastTick = ticks
+		g.sequence = 0
+		return g.lastTick, g.sequence, nil
+	}
+
+	if g.sequence < maxEncodable(g.base, width) {
+		g.sequence++
+		return g.lastTick, g.sequence, nil
+	}
+
+	switch g.config.overflowPolicy {
+	case OverflowBorrow:
+		g.lastTick++
+	case OverflowError:
+		return 0, 0, ErrSequenceExhausted
+	default:
+		// Holding the lock while waiting is fine, as other callers would have to wait anyway.
+		for ticks <= g.lastTick {
+			nextTick := g.config.epoch.Add(time.Duration(g.lastTick+1) * g.config.tickSize)
+			time.Sleep(nextTick.Sub(g.config.timeProvider()))
+			var err error
+			ticks, err = g.currentTicks()
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+		g.lastTick = ticks
+	}
+	g.sequence = 0
+	return g.lastTick, g.sequence, nil
+}
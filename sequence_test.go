@@ -0,0 +1,183 @@
+package flexid
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceTestConfig returns a config whose IDs are just the tick and a single digit sequence number,
+// with the time controlled by the returned pointer.
+func sequenceTestConfig(policy OverflowPolicy) (Config, *time.Duration) {
+	config, offset := fakeClockTestConfig("0123456789", 0)
+	return config.WithSequence(1, policy), offset
+}
+
+func Test_Sequence(t *testing.T) {
+	config, offset := sequenceTestConfig(OverflowError)
+	gen := MustNewGenerator(config)
+
+	*offset = 5 * time.Second
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, gen.MustGenerate())
+	}
+	*offset = 6 * time.Second
+	ids = append(ids, gen.MustGenerate())
+
+	expected := []string{"50", "51", "52", "60"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected IDs %v, got %v", expected, ids)
+	}
+
+	parsed, err := gen.Parse("52")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed.Ticks != 5 || parsed.Sequence != 2 {
+		t.Errorf("Unexpected parse result: %+v", parsed)
+	}
+}
+
+func Test_Sequence_ClockGoesBackwards(t *testing.T) {
+	config, offset := sequenceTestConfig(OverflowError)
+	gen := MustNewGenerator(config)
+
+	*offset = 5 * time.Second
+	first := gen.MustGenerate()
+	*offset = 4 * time.Second
+	second := gen.MustGenerate()
+
+	if first != "50" || second != "51" {
+		t.Errorf("Expected the last tick to be reused when the clock goes backwards, got %q then %q", first, second)
+	}
+}
+
+func Test_Sequence_OverflowError(t *testing.T) {
+	config, offset := sequenceTestConfig(OverflowError)
+	gen := MustNewGenerator(config)
+
+	*offset = 5 * time.Second
+	for i := 0; i < 10; i++ {
+		gen.MustGenerate()
+	}
+	if _, err := gen.Generate(); !errors.Is(err, ErrSequenceExhausted) {
+		t.Errorf("Expected ErrSequenceExhausted, got %v", err)
+	}
+}
+
+func Test_Sequence_OverflowBorrow(t *testing.T) {
+	config, offset := sequenceTestConfig(OverflowBorrow)
+	gen := MustNewGenerator(config)
+
+	*offset = 5 * time.Second
+	for i := 0; i < 10; i++ {
+		gen.MustGenerate()
+	}
+
+	var ids []string
+	ids = append(ids, gen.MustGenerate(), gen.MustGenerate()) // Borrowed from tick 6
+	*offset = 6 * time.Second
+	ids = append(ids, gen.MustGenerate()) // Continues the borrowed tick
+	*offset = 7 * time.Second
+	ids = append(ids, gen.MustGenerate())
+
+	expected := []string{"60", "61", "62", "70"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected IDs %v, got %v", expected, ids)
+	}
+}
+
+func Test_Sequence_OverflowWait(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithTickSize(Millisecond).
+		WithNumRandomChars(0).
+		WithAlphabet("01").
+		WithSequence(1, OverflowWait))
+
+	var ids []string
+	for i := 0; i < 10; i++ {
+		ids = append(ids, gen.MustGenerate())
+	}
+
+	if !slices.IsSorted(ids) {
+		t.Errorf("Expected IDs to be sorted, got %v", ids)
+	}
+	if len(slices.Compact(slices.Clone(ids))) != len(ids) {
+		t.Errorf("Expected unique IDs, got %v", ids)
+	}
+}
+
+func Test_Sequence_Concurrent(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithTickSize(Millisecond).
+		WithNumRandomChars(0).
+		WithSequence(2, OverflowWait))
+
+	const goroutines = 8
+	const perGoroutine = 500
+	results := make([][]string, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				results[i] = append(results[i], gen.MustGenerate())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{}, goroutines*perGoroutine)
+	for _, ids := range results {
+		for _, id := range ids {
+			if _, exists := seen[id]; exists {
+				t.Fatalf("Duplicate ID generated: %q", id)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func Test_Sequence_Layout(t *testing.T) {
+	config, offset := sequenceTestConfig(OverflowError)
+	gen := MustNewGenerator(config.
+		WithNodeID(3, 1).
+		WithLayout(MustParseLayout("{time:3}.{node}.{seq:2}")))
+
+	*offset = 5 * time.Second
+	gen.MustGenerate()
+	id := gen.MustGenerate()
+	if id != "005.3.01" {
+		t.Errorf("Expected ID %q, got %q", "005.3.01", id)
+	}
+
+	parsed := gen.MustParse(id)
+	if parsed.Ticks != 5 || parsed.Node != 3 || parsed.Sequence != 1 {
+		t.Errorf("Unexpected parse result for %q: %+v", id, parsed)
+	}
+}
+
+func Test_Sequence_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Negative Width", NewConfig().WithSequence(-1, OverflowWait)},
+		{"No Tick Size", NewConfig().WithTickSize(0).WithSequence(2, OverflowWait)},
+		{"Unknown Policy", NewConfig().WithSequence(2, OverflowPolicy(42))},
+		{"Layout Without Sequence", NewConfig().WithSequence(2, OverflowWait).WithLayout(MustParseLayout("{time}{rand}"))},
+		{"Sequence Without Config", NewConfig().WithLayout(MustParseLayout("{time}{seq:2}"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package flexid
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ID is an ID paired with the generator it was generated with, so it can be logged along with its
+// creation time and age.
+//
+//	logger.Info("order created", "order", generator.ID(orderID))
+type ID struct {
+	value string
+	gen   *Generator
+}
+
+// ID returns the ID for logging. It needn't be valid; invalid IDs are logged as given.
+func (g *Generator) ID(id string) ID {
+	return ID{value: id, gen: g}
+}
+
+// String returns the ID as given.
+func (id ID) String() string {
+	return id.value
+}
+
+// LogValue implements slog.LogValuer, rendering the ID as a group of the ID itself, the start of the tick it
+// was generated in, and its age as of the generator's current time. The time and age are omitted if the ID
+// has no time component or doesn't parse.
+func (id ID) LogValue() slog.Value {
+	idAttr := slog.String("id", id.value)
+	if id.gen == nil {
+		return slog.GroupValue(idAttr)
+	}
+	parsed, err := id.gen.Parse(id.value)
+	if err != nil || parsed.Time.IsZero() {
+		return slog.GroupValue(idAttr)
+	}
+
+	age := id.gen.config.timeProvider().Sub(parsed.Time)
+	return slog.GroupValue(
+		idAttr,
+		slog.Time("time", parsed.Time),
+		slog.Duration("age", age.Round(time.Millisecond)),
+	)
+}
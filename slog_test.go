@@ -0,0 +1,70 @@
+package flexid
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_ID_LogValue(t *testing.T) {
+	offset := 90*time.Minute + 1500*time.Millisecond
+	gen := MustNewGenerator(withFakeClock(NewConfig().WithTickSize(Second), &offset))
+
+	id := gen.MustGenerate()
+	offset += time.Minute
+
+	attrs := gen.ID(id).LogValue().Group()
+	if len(attrs) != 3 {
+		t.Fatalf("Expected 3 attributes, got %v", attrs)
+	}
+	if attrs[0].Key != "id" || attrs[0].Value.String() != id {
+		t.Errorf("Expected id=%s, got %v", id, attrs[0])
+	}
+	if expected := fakeClockEpoch.Add(90*time.Minute + time.Second); attrs[1].Key != "time" || !attrs[1].Value.Time().Equal(expected) {
+		t.Errorf("Expected time=%v, got %v", expected, attrs[1])
+	}
+	if attrs[2].Key != "age" || attrs[2].Value.Duration() != time.Minute+500*time.Millisecond {
+		t.Errorf("Expected age=1m0.5s, got %v", attrs[2])
+	}
+}
+
+func Test_ID_LogValue_NoTime(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithTickSize(0))
+
+	for _, id := range []string{gen.MustGenerate(), "not an ID!"} {
+		attrs := gen.ID(id).LogValue().Group()
+		if len(attrs) != 1 || attrs[0].Key != "id" || attrs[0].Value.String() != id {
+			t.Errorf("Expected only id=%s, got %v", id, attrs)
+		}
+	}
+
+	if attrs := (ID{}).LogValue().Group(); len(attrs) != 1 {
+		t.Errorf("Expected the zero ID to log only its id, got %v", attrs)
+	}
+}
+
+func Test_ID_Logged(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	gen := MustNewGenerator(NewConfig().WithTimeProvider(func() time.Time { return now }))
+	id := gen.MustGenerate()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("created", "order", gen.ID(id))
+
+	expected := "level=INFO msg=created order.id=" + id + " order.time=2025-10-16T12:00:00.000Z order.age=0s\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected:\n%sGot:\n%s", expected, got)
+	}
+	if got := gen.ID(id).String(); got != id {
+		t.Errorf("Expected String to return %q, got %q", id, got)
+	}
+}
@@ -0,0 +1,30 @@
+package flexid
+
+// Stats is a snapshot of a Generator's counters.
+type Stats struct {
+	Generated   uint64 // Number of IDs successfully generated.
+	Regenerated uint64 // Number of random parts discarded for containing a blocked word.
+	Duplicates  uint64 // Number of random parts discarded for duplicating an ID from the same tick (see WithDedup).
+	Unguarded   uint64 // Number of IDs issued without being remembered for dedup, as the tick's limit was reached.
+	Conflicts   uint64 // Number of random parts discarded for an ID already claimed in the registry (see WithRegistry).
+}
+
+// Stats returns a snapshot of the generator's counters.
+func (g *Generator) Stats() Stats {
+	return Stats{
+		Generated:   g.generated.Load(),
+		Regenerated: g.regenerated.Load(),
+		Duplicates:  g.duplicates.Load(),
+		Unguarded:   g.unguarded.Load(),
+		Conflicts:   g.conflicts.Load(),
+	}
+}
+
+// RegenerationRate returns the average number of discarded random parts per generated ID, whether for
+// containing a blocked word, being a duplicate, or conflicting in the registry.
+func (s Stats) RegenerationRate() float64 {
+	if s.Generated == 0 {
+		return 0
+	}
+	return float64(s.Regenerated+s.Duplicates+s.Conflicts) / float64(s.Generated)
+}
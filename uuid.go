@@ -0,0 +1,221 @@
+package flexid
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// UUIDFormat determines how Config.WithUUIDv7 renders UUIDs.
+type UUIDFormat int
+
+const (
+	// UUIDHex renders UUIDs in the canonical form, e.g. "0199ece4-2a00-746d-9ac0-7e18c2a820ae".
+	UUIDHex UUIDFormat = iota
+	// UUIDAlphabet renders UUIDs as a 128-bit number in the generator's alphabet, left-padded to a fixed width
+	// with the alphabet's first character, e.g. 22 characters for Base62.
+	UUIDAlphabet
+)
+
+const (
+	uuidTimeBits   = 48 // Bits of Unix milliseconds at the start of a UUIDv7.
+	uuidRandomBits = 74 // Bits of randomness, after the version and variant bits.
+	uuidHexLen     = 36 // Length of the canonical form, including dashes.
+	uuidHexTimeLen = 13 // Length of the canonical form's timestamp, "xxxxxxxx-xxxx".
+)
+
+// WithUUIDv7 makes the generator produce RFC 9562 UUIDv7s: a 48-bit Unix millisecond timestamp, the version
+// and variant bits, then 74 random bits. IDs can then be stored in UUID columns, and are still decoded by
+// Parse. The generator must keep the default Unix epoch and millisecond tick size, and the number of random
+// characters is ignored. Layouts, word lists, grouping, node IDs, sequences and the pronounceable random
+// strategy aren't supported, while blocklists, deduplication and registries are. Canonical UUIDs sort by
+// time, as do alphabet-rendered UUIDs if the alphabet is in ascending byte order, like Base62.
+func (c Config) WithUUIDv7(format UUIDFormat) Config {
+	c.uuidV7 = true
+	c.uuidFormat = format
+	return c
+}
+
+// validateUUID ensures the config can produce UUIDv7s.
+func validateUUID(config Config) error {
+	if !config.uuidV7 {
+		return nil
+	}
+
+	switch {
+	case config.uuidFormat != UUIDHex && config.uuidFormat != UUIDAlphabet:
+		return fmt.Errorf("unknown UUID format: %d", config.uuidFormat)
+	case !config.epoch.Equal(DefaultEpoch):
+		return errors.New("UUIDv7 requires the Unix epoch")
+	case config.tickSize != Millisecond:
+		return errors.New("UUIDv7 requires a millisecond tick size")
+	case config.words != nil:
+		return errors.New("UUIDv7 cannot be combined with a word list")
+	case len(config.layout.segments) > 0:
+		return errors.New("UUIDv7 cannot be combined with a layout")
+	case config.separator != "":
+		return errors.New("UUIDv7 cannot be combined with grouping")
+	case config.nodeWidth > 0:
+		return errors.New("UUIDv7 cannot be combined with a node ID")
+	case config.sequenceWidth > 0:
+		return errors.New("UUIDv7 cannot be combined with a sequence")
+	case config.randomStrategy != RandomAlphabet:
+		return errors.New("UUIDv7 cannot be combined with the pronounceable random strategy")
+	}
+	return nil
+}
+
+// generateUUID generates a UUIDv7 for the tick, regenerating its random bits while it contains a blocked
+// word or isn't unique.
+func (g *Generator) generateUUID(ctx context.Context, ticks uint64) (string, error) {
+	if ticks >= 1<<uuidTimeBits {
+		return "", errors.New("current time is beyond the range of UUIDv7's 48-bit timestamp")
+	}
+
+	var uuid [16]byte
+	for attempt := 1; ; attempt++ {
+		if _, err := io.ReadFull(g.config.randomSource, uuid[6:]); err != nil {
+			return "", errors.New("failed to read random bytes: " + err.Error())
+		}
+		putUUIDTime(&uuid, ticks)
+
+		id := g.formatUUID(uuid)
+		reason := rejectBlocked
+		if len(g.config.blocklist) == 0 || !g.containsBlockedWord(id, g.uuidRandomRange(id)) {
+			var err error
+			reason, err = g.claimUnique(ctx, ticks, id)
+			if err != nil {
+				return "", err
+			}
+		}
+		if reason == accepted {
+			g.generated.Add(1)
+			return id, nil
+		}
+		if err := g.rejectRandom(attempt, reason, true); err != nil {
+			return "", err
+		}
+	}
+}
+
+// putUUIDTime sets the UUID's timestamp to the Unix milliseconds, and its version and variant bits.
+func putUUIDTime(uuid *[16]byte, millis uint64) {
+	uuid[0] = byte(millis >> 40)
+	uuid[1] = byte(millis >> 32)
+	binary.BigEndian.PutUint32(uuid[2:6], uint32(millis))
+	uuid[6] = 0x70 | uuid[6]&0x0f
+	uuid[8] = 0x80 | uuid[8]&0x3f
+}
+
+// uuidRandomRange returns the [start, end) range of the rendered UUID's characters affected by its random bits.
+func (g *Generator) uuidRandomRange(id string) [2]int {
+	if g.config.uuidFormat == UUIDHex {
+		return [2]int{uuidHexTimeLen, len(id)}
+	}
+	return [2]int{len(id) - digitsFor(g.base, 128-uuidTimeBits), len(id)}
+}
+
+// formatUUID renders the UUID in the configured format.
+func (g *Generator) formatUUID(uuid [16]byte) string {
+	if g.config.uuidFormat == UUIDHex {
+		return formatUUIDHex(uuid)
+	}
+
+	hi, lo := binary.BigEndian.Uint64(uuid[:8]), binary.BigEndian.Uint64(uuid[8:])
+	buf := make([]byte, digitsFor(g.base, 128))
+	for i := len(buf) - 1; i >= 0; i-- {
+		var remainder uint64
+		hi, remainder = bits.Div64(0, hi, uint64(g.base))
+		lo, remainder = bits.Div64(remainder, lo, uint64(g.base))
+		buf[i] = g.config.alphabet[remainder]
+	}
+	return string(buf)
+}
+
+// parseUUID decodes a UUIDv7 rendered in the configured format. The random part is the hex of the UUID's
+// last 10 bytes, including the version and variant bits.
+func (g *Generator) parseUUID(id string) (ParsedID, error) {
+	var uuid [16]byte
+	if g.config.uuidFormat == UUIDHex {
+		var err error
+		uuid, err = parseUUIDHex(id)
+		if err != nil {
+			return ParsedID{}, err
+		}
+	} else {
+		if width := digitsFor(g.base, 128); len(id) != width {
+			return ParsedID{}, fmt.Errorf("ID %q must be %d characters long", id, width)
+		}
+		var hi, lo uint64
+		for i := 0; i < len(id); i++ {
+			index := g.indexes[id[i]]
+			if index < 0 {
+				return ParsedID{}, fmt.Errorf("invalid ID %q: character %q is not in the alphabet", id, id[i])
+			}
+			carry, low := bits.Mul64(lo, uint64(g.base))
+			low, c := bits.Add64(low, uint64(index), 0)
+			overflow, high := bits.Mul64(hi, uint64(g.base))
+			high, c = bits.Add64(high, carry+c, 0)
+			if overflow != 0 || c != 0 {
+				return ParsedID{}, fmt.Errorf("invalid ID %q: number overflows 128 bits", id)
+			}
+			hi, lo = high, low
+		}
+		binary.BigEndian.PutUint64(uuid[:8], hi)
+		binary.BigEndian.PutUint64(uuid[8:], lo)
+	}
+
+	if uuid[6]>>4 != 7 || uuid[8]>>6 != 2 {
+		return ParsedID{}, fmt.Errorf("ID %q is not a UUIDv7", id)
+	}
+	ticks := uint64(uuid[0])<<40 | uint64(uuid[1])<<32 | uint64(binary.BigEndian.Uint32(uuid[2:6]))
+	t, err := g.tickTime(ticks)
+	if err != nil {
+		return ParsedID{}, err
+	}
+	return ParsedID{Ticks: ticks, Time: t, Random: hex.EncodeToString(uuid[6:])}, nil
+}
+
+// formatUUIDHex renders the UUID in the canonical 8-4-4-4-12 form, in lowercase.
+func formatUUIDHex(uuid [16]byte) string {
+	buf := make([]byte, uuidHexLen)
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf)
+}
+
+// parseUUIDHex decodes a UUID in the canonical 8-4-4-4-12 form, in either case.
+func parseUUIDHex(s string) ([16]byte, error) {
+	var uuid [16]byte
+	if len(s) != uuidHexLen || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return uuid, fmt.Errorf("UUID %q is not in the canonical 8-4-4-4-12 form", s)
+	}
+
+	j := 0
+	for _, part := range [][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}} {
+		n, err := hex.Decode(uuid[j:], []byte(s[part[0]:part[1]]))
+		if err != nil {
+			return [16]byte{}, fmt.Errorf("invalid UUID %q: %w", s, err)
+		}
+		j += n
+	}
+	return uuid, nil
+}
+
+// digitsFor returns the number of digits in the given base needed to represent any number of the given bits.
+func digitsFor(base, numBits int) int {
+	// Small tolerance so exact powers of two aren't rounded up due to float error.
+	return int(math.Ceil(float64(numBits)/math.Log2(float64(base)) - 1e-9))
+}
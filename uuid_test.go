@@ -0,0 +1,195 @@
+package flexid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcExampleTime is the time of the UUIDv7 example in RFC 9562, Appendix A.6.
+var rfcExampleTime = time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+// rfcExampleRandom are random bytes giving the RFC's example once the version and variant bits are set.
+var rfcExampleRandom = []byte{0x0c, 0xc3, 0x18, 0xc4, 0xdc, 0x0c, 0x0c, 0x07, 0x39, 0x8f}
+
+func Test_UUIDv7_Hex(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithUUIDv7(UUIDHex).
+		WithTimeProvider(func() time.Time { return rfcExampleTime }).
+		WithRandomSource(&sequenceReader{bytes: rfcExampleRandom}))
+
+	id := gen.MustGenerate()
+	if expected := "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"; id != expected {
+		t.Fatalf("Expected %s, got %s", expected, id)
+	}
+
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if !parsed.Time.Equal(rfcExampleTime) || parsed.Ticks != 0x017f22e279b0 {
+		t.Errorf("Expected time %v, got %v (%d ticks)", rfcExampleTime, parsed.Time, parsed.Ticks)
+	}
+	if parsed.Random != "7cc398c4dc0c0c07398f" {
+		t.Errorf("Expected the random part 7cc398c4dc0c0c07398f, got %s", parsed.Random)
+	}
+
+	if _, err := gen.Parse(strings.ToUpper(id)); err != nil {
+		t.Errorf("Expected an uppercase UUID to parse, got %v", err)
+	}
+}
+
+func Test_UUIDv7_VersionAndVariant(t *testing.T) {
+	for _, b := range []byte{0x00, 0xff} {
+		gen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDHex).WithRandomSource(&sameByteReader{b: b}))
+		id := gen.MustGenerate()
+		if id[14] != '7' || !strings.ContainsRune("89ab", rune(id[19])) {
+			t.Errorf("Expected version 7 and the RFC 9562 variant, got %s", id)
+		}
+	}
+}
+
+func Test_UUIDv7_Alphabet(t *testing.T) {
+	now := rfcExampleTime
+	gen := MustNewGenerator(NewConfig().
+		WithUUIDv7(UUIDAlphabet).
+		WithTimeProvider(func() time.Time { return now }).
+		WithRandomSource(&sequenceReader{bytes: rfcExampleRandom}))
+
+	id := gen.MustGenerate()
+	if len(id) != 22 {
+		t.Fatalf("Expected 22 Base62 characters, got %q", id)
+	}
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if !parsed.Time.Equal(rfcExampleTime) || parsed.Random != "7cc398c4dc0c0c07398f" {
+		t.Errorf("Expected the RFC example's time and random part, got %+v", parsed)
+	}
+
+	now = now.Add(time.Millisecond)
+	if later := gen.MustGenerate(); later <= id {
+		t.Errorf("Expected %s to sort after %s", later, id)
+	}
+}
+
+func Test_UUIDv7_AlphabetWidths(t *testing.T) {
+	testCases := []struct {
+		alphabet string
+		width    int
+	}{
+		{Base16LowerAlphabet, 32},
+		{CrockfordBase32Alphabet, 26},
+		{Base36Alphabet, 25},
+		{Base62Alphabet, 22},
+		{Base64UrlAlphabet, 22},
+		{"01", 128},
+	}
+
+	for _, tc := range testCases {
+		gen := MustNewGenerator(NewConfig().
+			WithUUIDv7(UUIDAlphabet).
+			WithAlphabet(tc.alphabet).
+			WithRandomSource(&sameByteReader{b: 0xff}))
+		id := gen.MustGenerate()
+		if len(id) != tc.width {
+			t.Errorf("%s: expected %d characters, got %q", tc.alphabet, tc.width, id)
+		}
+		if _, err := gen.Parse(id); err != nil {
+			t.Errorf("%s: Parse(%q) failed: %v", tc.alphabet, id, err)
+		}
+	}
+}
+
+func Test_UUIDv7_ParseInvalid(t *testing.T) {
+	hexGen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDHex))
+	for _, id := range []string{
+		"",
+		"017f22e279b07cc398c4dc0c0c07398f",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398g",
+		"017f22e2-79b0-4cc3-98c4-dc0c0c07398f", // Version 4
+		"017f22e2-79b0-7cc3-c8c4-dc0c0c07398f", // Microsoft variant
+	} {
+		if _, err := hexGen.Parse(id); err == nil {
+			t.Errorf("Expected an error parsing %q, but got nil", id)
+		}
+	}
+
+	alphabetGen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDAlphabet))
+	for _, id := range []string{
+		"abc",
+		"zzzzzzzzzzzzzzzzzzzzzz", // Overflows 128 bits
+		"0000000000000000000000", // Version 0
+		"00000000000000000000_0",
+	} {
+		if _, err := alphabetGen.Parse(id); err == nil {
+			t.Errorf("Expected an error parsing %q, but got nil", id)
+		}
+	}
+}
+
+func Test_UUIDv7_InvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Unknown Format", NewConfig().WithUUIDv7(UUIDFormat(9))},
+		{"Custom Epoch", NewConfig().WithUUIDv7(UUIDHex).WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"Second Ticks", NewConfig().WithUUIDv7(UUIDHex).WithTickSize(Second)},
+		{"No Time", NewConfig().WithUUIDv7(UUIDHex).WithTickSize(0)},
+		{"Word List", NewConfig().WithUUIDv7(UUIDHex).WithWordList(Words256, "-")},
+		{"Layout", NewConfig().WithUUIDv7(UUIDHex).WithLayout(MustParseLayout("{time}{rand}"))},
+		{"Grouping", NewConfig().WithUUIDv7(UUIDAlphabet).WithGrouping("-", 4)},
+		{"Node ID", NewConfig().WithUUIDv7(UUIDHex).WithNodeID(1, 2)},
+		{"Sequence", NewConfig().WithUUIDv7(UUIDHex).WithSequence(2, OverflowWait)},
+		{"Pronounceable", NewConfig().WithUUIDv7(UUIDAlphabet).WithRandomStrategy(RandomPronounceable)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func Test_UUIDv7_Blocklist(t *testing.T) {
+	// The first UUID ends in "dead...", the second in "1111...".
+	random := []byte{0x00, 0x00, 0x00, 0x00, 0xde, 0xad, 0x00, 0x00, 0x00, 0x00, 0x11}
+	gen := MustNewGenerator(NewConfig().
+		WithUUIDv7(UUIDHex).
+		WithBlocklist("dead").
+		WithTimeProvider(func() time.Time { return rfcExampleTime }).
+		WithRandomSource(&sequenceReader{bytes: random}))
+
+	id := gen.MustGenerate()
+	if strings.Contains(id, "dead") {
+		t.Errorf("Expected the blocked word to be regenerated away, got %s", id)
+	}
+	if stats := gen.Stats(); stats.Regenerated != 1 || stats.Generated != 1 {
+		t.Errorf("Expected 1 regeneration, got %+v", stats)
+	}
+}
+
+func Test_UUIDv7_Dedup(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().
+		WithUUIDv7(UUIDHex).
+		WithDedup(10).
+		WithTimeProvider(func() time.Time { return rfcExampleTime }).
+		WithRandomSource(&sequenceReader{bytes: append(append([]byte{}, rfcExampleRandom...), rfcExampleRandom...)}))
+
+	first, second := gen.MustGenerate(), gen.MustGenerate()
+	if first == second || gen.Stats().Duplicates != 1 {
+		t.Errorf("Expected a duplicate to be regenerated, got %s and %s (%+v)", first, second, gen.Stats())
+	}
+}
+
+func Test_UUIDv7_EntropyBits(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithUUIDv7(UUIDAlphabet).WithNumRandomChars(2))
+	if bits := gen.EntropyBits(); bits != 74 {
+		t.Errorf("Expected 74 bits, got %v", bits)
+	}
+}
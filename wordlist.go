@@ -0,0 +1,200 @@
+package flexid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Words256 is a built-in list of 256 short, common English words which are easy to read aloud,
+// for use with Config.WithWordList.
+var Words256 = []string{
+	"able", "acorn", "actor", "aisle", "album", "alley", "alpine", "amber", "anchor", "angle", "ankle", "apple",
+	"april", "apron", "arena", "armor", "arrow", "atlas", "attic", "autumn", "avenue", "awake", "bacon", "badge",
+	"bagel", "baker", "bamboo", "banjo", "barrel", "basil", "basin", "beach", "beacon", "beard", "bench", "berry",
+	"bike", "birch", "bison", "blade", "blanket", "blaze", "bloom", "blue", "border", "bottle", "brave", "bread",
+	"brick", "bridge", "brisk", "broom", "brush", "bubble", "bucket", "buddy", "bugle", "butter", "cabin",
+	"cactus", "camel", "candle", "canoe", "canyon", "cargo", "carpet", "carrot", "castle", "cedar", "cello",
+	"chalk", "cherry", "chess", "chief", "cider", "circle", "citrus", "clever", "cliff", "clock", "cloud",
+	"clover", "cocoa", "comet", "coral", "cotton", "cousin", "coyote", "crane", "crayon", "creek", "cricket",
+	"crown", "crystal", "cycle", "daisy", "dancer", "denim", "desert", "dingo", "dolphin", "donkey", "dragon",
+	"dream", "drift", "drum", "eagle", "easel", "echo", "eclipse", "elbow", "elder", "ember", "emerald", "epic",
+	"fable", "falcon", "fern", "ferry", "fiddle", "finch", "flame", "flint", "flute", "forest", "fossil", "fox",
+	"frost", "galaxy", "garden", "garlic", "gecko", "gentle", "ginger", "glacier", "glow", "golden", "gopher",
+	"grape", "gravel", "guitar", "gust", "hammer", "harbor", "harvest", "hazel", "helmet", "heron", "honey",
+	"hotel", "humble", "husky", "igloo", "indigo", "island", "ivory", "jacket", "jaguar", "jasmine", "jelly",
+	"jewel", "jolly", "jungle", "kayak", "kettle", "kiwi", "koala", "ladder", "lagoon", "lantern", "lemon",
+	"lilac", "lily", "lion", "lizard", "llama", "locket", "lotus", "lucky", "lunar", "mango", "maple", "marble",
+	"meadow", "melon", "mint", "mocha", "monkey", "moose", "muffin", "nectar", "needle", "noble", "nutmeg",
+	"oasis", "ocean", "olive", "onion", "opal", "orbit", "orchid", "otter", "oyster", "paddle", "panda", "paper",
+	"parrot", "pebble", "pepper", "piano", "pilot", "pine", "planet", "plum", "polar", "pony", "poppy", "prism",
+	"puzzle", "quartz", "quest", "quiet", "rabbit", "raven", "river", "robin", "rocket", "ruby", "saddle",
+	"salmon", "scarf", "shadow", "silver", "sketch", "sloth", "solar", "spark", "spice", "spruce", "squid",
+	"storm", "sugar", "summer", "sunny", "swan", "tiger", "timber", "torch", "tulip", "tundra", "turtle",
+	"velvet", "violet", "walnut", "whale", "willow",
+}
+
+// WithWordList uses whole words as symbols instead of the alphabet's characters, so IDs read as a phrase
+// such as "brave-otter-river". Both the time component and the random part are encoded in words, joined
+// by the joiner, and numRandomChars becomes the number of random words. Note that IDs generated with
+// a word list are generally not lexicographically sortable.
+func (c Config) WithWordList(words []string, joiner string) Config {
+	c.words = words
+	c.joiner = joiner
+	return c
+}
+
+// validateWordList ensures words are unique and can be unambiguously split apart again.
+func validateWordList(config Config) error {
+	if config.words == nil {
+		return nil
+	}
+
+	if len(config.words) < 2 {
+		return errors.New("word list must contain at least 2 words")
+	}
+	if len(config.words) > 1<<16 {
+		return fmt.Errorf("word list cannot contain more than %d words", 1<<16)
+	}
+	if config.joiner == "" {
+		return errors.New("joiner cannot be empty when using a word list")
+	}
+	if config.separator != "" {
+		return errors.New("grouping cannot be combined with a word list")
+	}
+
+	seen := make(map[string]struct{}, len(config.words))
+	for _, word := range config.words {
+		if word == "" {
+			return errors.New("word list cannot contain empty words")
+		}
+		if strings.Contains(word, config.joiner) {
+			return fmt.Errorf("word %q contains the joiner %q", word, config.joiner)
+		}
+		key := word
+		if config.collationSafe {
+			key = strings.ToLower(word)
+		}
+		if _, exists := seen[key]; exists {
+			return fmt.Errorf("word list contains duplicate word: %s", word)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// encodeWords encodes a non-negative integer using the generator's word list, most significant word first.
+func (g *Generator) encodeWords(number uint64) string {
+	var digits []int
+	for {
+		digits = append(digits, int(number%uint64(g.base)))
+		number /= uint64(g.base)
+		if number == 0 {
+			break
+		}
+	}
+
+	var sb strings.Builder
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteString(g.config.words[digits[i]])
+		if i > 0 {
+			sb.WriteString(g.config.joiner)
+		}
+	}
+	return sb.String()
+}
+
+// decodeWords decodes words encoded by encodeWords.
+func (g *Generator) decodeWords(words []string) (uint64, error) {
+	if len(words) == 0 {
+		return 0, errors.New("cannot decode an empty list of words")
+	}
+
+	var number uint64
+	for _, word := range words {
+		index, ok := g.wordIndexes[word]
+		if !ok {
+			return 0, fmt.Errorf("word %q is not in the word list", word)
+		}
+		if number > (^uint64(0)-uint64(index))/uint64(g.base) {
+			return 0, errors.New("encoded number overflows uint64")
+		}
+		number = number*uint64(g.base) + uint64(index)
+	}
+	return number, nil
+}
+
+// generateRandomWords generates the given number of cryptographically secure random words joined by the
+// joiner, avoiding modulo bias via rejection sampling. Word lists larger than 256 use two bytes per sample.
+func (g *Generator) generateRandomWords(count int) (string, error) {
+	if count == 0 {
+		return "", nil
+	}
+
+	bytesPerSample := 1
+	if g.base > 1<<8 {
+		bytesPerSample = 2
+	}
+	sampleRange := 1 << (8 * bytesPerSample)
+	maxValidSample := (sampleRange/g.base)*g.base - 1
+
+	var sb strings.Builder
+	randomBytes := make([]byte, count*bytesPerSample)
+	for i := 0; i < count; {
+		if _, err := io.ReadFull(g.config.randomSource, randomBytes); err != nil {
+			return "", errors.New("failed to read random bytes: " + err.Error())
+		}
+
+		for j := 0; j+bytesPerSample <= len(randomBytes) && i < count; j += bytesPerSample {
+			sample := int(randomBytes[j])
+			if bytesPerSample == 2 {
+				sample = sample<<8 | int(randomBytes[j+1])
+			}
+			if sample > maxValidSample {
+				continue // Discard biased sample
+			}
+			if i > 0 {
+				sb.WriteString(g.config.joiner)
+			}
+			sb.WriteString(g.config.words[sample%g.base])
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+// parseWords is Parse for generators using a word list.
+func (g *Generator) parseWords(id string) (ParsedID, error) {
+	words := strings.Split(id, g.config.joiner)
+
+	timestampLen := len(words) - g.config.numRandomChars
+	if id == "" || timestampLen < 0 || (g.config.tickSize > 0 && timestampLen == 0) {
+		return ParsedID{}, fmt.Errorf("ID %q has too few words", id)
+	}
+	if g.config.tickSize <= 0 && timestampLen > 0 {
+		return ParsedID{}, fmt.Errorf("ID %q has too many words", id)
+	}
+
+	var parsed ParsedID
+	if g.config.tickSize > 0 {
+		ticks, err := g.decodeWords(words[:timestampLen])
+		if err != nil {
+			return ParsedID{}, fmt.Errorf("invalid time component in ID %q: %w", id, err)
+		}
+		parsed.Ticks = ticks
+		parsed.Time, err = g.tickTime(ticks)
+		if err != nil {
+			return ParsedID{}, err
+		}
+	}
+
+	for _, word := range words[timestampLen:] {
+		if _, ok := g.wordIndexes[word]; !ok {
+			return ParsedID{}, fmt.Errorf("invalid random part in ID %q: word %q is not in the word list", id, word)
+		}
+	}
+	parsed.Random = strings.Join(words[timestampLen:], g.config.joiner)
+
+	return parsed, nil
+}
@@ -0,0 +1,109 @@
+package flexid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Words256(t *testing.T) {
+	if len(Words256) != 256 {
+		t.Errorf("Expected 256 words, got %d", len(Words256))
+	}
+	if _, err := NewGenerator(NewConfig().WithWordList(Words256, "-")); err != nil {
+		t.Errorf("Expected built-in word list to be valid, got: %v", err)
+	}
+}
+
+func Test_WordList_GenerateAndParse(t *testing.T) {
+	offset := (256*3 + 5) * time.Second
+	gen := MustNewGenerator(withFakeClock(NewConfig().
+		WithTickSize(Second).
+		WithNumRandomChars(2).
+		WithWordList(Words256, "-").
+		WithRandomSource(&sameByteReader{b: 7}), &offset))
+
+	id := gen.MustGenerate()
+	expected := strings.Join([]string{Words256[3], Words256[5], Words256[7], Words256[7]}, "-")
+	if id != expected {
+		t.Errorf("Expected ID %q, got %q", expected, id)
+	}
+
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", id, err)
+	}
+	if parsed.Ticks != 256*3+5 {
+		t.Errorf("Ticks got %d, want %d", parsed.Ticks, 256*3+5)
+	}
+	if parsed.Random != Words256[7]+"-"+Words256[7] {
+		t.Errorf("Random got %q", parsed.Random)
+	}
+}
+
+func Test_WordList_Random(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithTickSize(0).WithNumRandomChars(4).WithWordList(Words256, " "))
+
+	id1 := gen.MustGenerate()
+	id2 := gen.MustGenerate()
+	if id1 == id2 {
+		t.Errorf("Expected different IDs, but got identical %q", id1)
+	}
+	if words := strings.Split(id1, " "); len(words) != 4 {
+		t.Errorf("Expected 4 words, got %q", id1)
+	}
+	if _, err := gen.Parse(id1); err != nil {
+		t.Errorf("Parse(%q) failed: %v", id1, err)
+	}
+}
+
+func Test_WordList_LargeList(t *testing.T) {
+	words := make([]string, 300)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", i)
+	}
+	gen := MustNewGenerator(NewConfig().
+		WithTickSize(0).
+		WithNumRandomChars(1).
+		WithWordList(words, ".").
+		WithRandomSource(&sameByteReader{b: 1}))
+
+	// Lists larger than 256 words sample two bytes at a time: 0x0101 = 257.
+	if id := gen.MustGenerate(); id != "w257" {
+		t.Errorf("Expected ID %q, got %q", "w257", id)
+	}
+}
+
+func Test_WordList_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Too Few Words", NewConfig().WithWordList([]string{"one"}, "-")},
+		{"Empty Joiner", NewConfig().WithWordList(Words256, "")},
+		{"Empty Word", NewConfig().WithWordList([]string{"one", ""}, "-")},
+		{"Duplicate Word", NewConfig().WithWordList([]string{"one", "two", "one"}, "-")},
+		{"Word Contains Joiner", NewConfig().WithWordList([]string{"one", "twenty-two"}, "-")},
+		{"Grouping", NewConfig().WithWordList(Words256, "-").WithGrouping(" ", 2)},
+		{"Collation Safe", NewConfig().WithWordList([]string{"one", "ONE"}, "-").WithCollationSafe(true)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGenerator(tc.config); err == nil {
+				t.Error("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func Test_WordList_ParseInvalid(t *testing.T) {
+	gen := MustNewGenerator(NewConfig().WithNumRandomChars(2).WithWordList(Words256, "-"))
+
+	for _, id := range []string{"", "able-acorn", "able-acorn-notaword", "notaword-able-acorn"} {
+		if parsed, err := gen.Parse(id); err == nil {
+			t.Errorf("Expected an error parsing %q, but got %+v", id, parsed)
+		}
+	}
+}